@@ -5,7 +5,7 @@ import (
 )
 
 //goland:noinspection GoTestName
-func ExampleVersion() {
+func Example_version() {
 	oldArgs := os.Args
 	defer func() { os.Args = oldArgs }()
 	os.Args = []string{"releasecontroller-mcp-server", "--version"}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"time"
+)
+
+// Types returned by the Cluster interface, describing the state of the cluster
+// of a job as gathered at its end.
+
+// PodSummary is the state of a pod, or of one of its containers
+type PodSummary struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+	Phase     string `json:"phase"`
+	// Container is set when the summary is about a single container of the pod
+	Container string `json:"container,omitempty"`
+	// Reason and Message explain why the pod is unhealthy, if it is
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// PodContainers lists the containers of a pod
+type PodContainers struct {
+	Namespace           string   `json:"namespace"`
+	Pod                 string   `json:"pod"`
+	InitContainers      []string `json:"initContainers,omitempty"`
+	Containers          []string `json:"containers,omitempty"`
+	EphemeralContainers []string `json:"ephemeralContainers,omitempty"`
+}
+
+// Condition is a status condition of an operator or a node
+type Condition struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// OperatorStatus is the Available, Progressing and Degraded conditions of a cluster operator
+type OperatorStatus struct {
+	Name        string     `json:"name"`
+	Available   *Condition `json:"available,omitempty"`
+	Progressing *Condition `json:"progressing,omitempty"`
+	Degraded    *Condition `json:"degraded,omitempty"`
+}
+
+// ClusterVersionSummary is the desired version and the update history of a cluster
+type ClusterVersionSummary struct {
+	Version          string          `json:"version"`
+	Image            string          `json:"image"`
+	URL              string          `json:"url,omitempty"`
+	AvailableUpdates []string        `json:"availableUpdates,omitempty"`
+	History          []UpdateHistory `json:"history,omitempty"`
+}
+
+// UpdateHistory is an entry of the update history of a cluster
+type UpdateHistory struct {
+	Version       string     `json:"version"`
+	State         string     `json:"state"`
+	Verified      bool       `json:"verified"`
+	Image         string     `json:"image"`
+	Started       time.Time  `json:"started"`
+	Completed     *time.Time `json:"completed,omitempty"`
+	AcceptedRisks string     `json:"acceptedRisks,omitempty"`
+}
+
+// NodeInfo is the system information reported by a node, fields are empty when
+// the node did not report them
+type NodeInfo struct {
+	Name                    string `json:"name"`
+	Architecture            string `json:"architecture"`
+	BootID                  string `json:"bootID"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+	KernelVersion           string `json:"kernelVersion"`
+	KubeProxyVersion        string `json:"kubeProxyVersion"`
+	KubeletVersion          string `json:"kubeletVersion"`
+	MachineID               string `json:"machineID"`
+	OperatingSystem         string `json:"operatingSystem"`
+	OSImage                 string `json:"osImage"`
+	SystemUUID              string `json:"systemUUID"`
+}
+
+// NodeLabels are the labels of a node
+type NodeLabels struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
+// NodeAnnotations are the annotations of a node
+type NodeAnnotations struct {
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// NodeConditions are the status conditions of a node
+type NodeConditions struct {
+	Name       string      `json:"name"`
+	Conditions []Condition `json:"conditions"`
+}
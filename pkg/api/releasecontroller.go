@@ -27,7 +27,8 @@ type VerificationStatus struct {
 	State   string `json:"state"`
 	URL     string `json:"url"`
 	Retries int    `json:"retries,omitempty"`
-	// TransitionTime *metav1.Time `json:"transitionTime,omitempty"`
+	// TransitionTime is when the job entered its current state
+	TransitionTime *time.Time `json:"transitionTime,omitempty"`
 }
 
 type VerificationStatusMap map[string]*VerificationStatus
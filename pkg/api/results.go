@@ -0,0 +1,307 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types returned by the ReleaseController interface
+
+// JobKind tells whether a release verification job gates the acceptance of a payload
+type JobKind string
+
+const (
+	JobKindBlocking  JobKind = "blocking"
+	JobKindInforming JobKind = "informing"
+	// JobKindUnknown is the kind of the jobs which have not started yet, the release
+	// controller does not tell whether they are blocking
+	JobKindUnknown JobKind = "unknown"
+)
+
+// ReleaseController describes a release controller of the registry
+type ReleaseController struct {
+	Name string `json:"name"`
+	Host string `json:"host"`
+	URL  string `json:"url"`
+	// Product is the product of the payloads, e.g. ocp or okd
+	Product string `json:"product,omitempty"`
+	// Architecture is the architecture of the payloads, multi for the multi-arch payloads
+	Architecture string   `json:"architecture,omitempty"`
+	Aliases      []string `json:"aliases,omitempty"`
+}
+
+// JobOutcome classifies the result of a verification job with its retries
+type JobOutcome string
+
+const (
+	JobOutcomePassedFirstTry   JobOutcome = "passed_first_try"
+	JobOutcomePassedAfterRetry JobOutcome = "passed_after_retry"
+	// JobOutcomeFailed is a job which failed and was not retried
+	JobOutcomeFailed JobOutcome = "failed"
+	// JobOutcomeExhaustedRetries is a job which failed on every attempt
+	JobOutcomeExhaustedRetries JobOutcome = "exhausted_retries"
+	JobOutcomePending          JobOutcome = "pending"
+	// JobOutcomeRetrying is a job running again after failed attempts
+	JobOutcomeRetrying JobOutcome = "retrying"
+)
+
+// VerificationJob is a job run to verify a release payload
+type VerificationJob struct {
+	Name  string  `json:"name"`
+	Kind  JobKind `json:"kind"`
+	State string  `json:"state"`
+	// URL is the Prow job URL of the run, the last attempt if the job was retried
+	URL string `json:"url"`
+	// Retries counts the attempts after the first one
+	Retries int        `json:"retries"`
+	Outcome JobOutcome `json:"outcome,omitempty"`
+}
+
+// Component is the version of a component shipped in a release payload, e.g. Kubernetes or CoreOS
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Issue is a Jira issue or CVE referenced by the commits of a release payload
+type Issue struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// PayloadStatus is the state of the verification jobs of a release payload
+type PayloadStatus struct {
+	Release string `json:"release"`
+	Phase   string `json:"phase"`
+	// Jobs are ordered by state (Failed, Pending, Succeeded), then kind and name
+	Jobs []PayloadJob `json:"jobs"`
+	// CanBeAccepted is false once the payload is rejected or a blocking job failed
+	CanBeAccepted bool `json:"canBeAccepted"`
+	// Verdict explains CanBeAccepted
+	Verdict string `json:"verdict"`
+}
+
+// PayloadJob is a verification job of a release payload
+type PayloadJob struct {
+	Name  string  `json:"name"`
+	Kind  JobKind `json:"kind"`
+	State string  `json:"state"`
+	// URL is the Prow job URL of the last attempt
+	URL     string     `json:"url,omitempty"`
+	Retries int        `json:"retries,omitempty"`
+	Outcome JobOutcome `json:"outcome,omitempty"`
+	// Since is when the job entered its state, e.g. started running
+	Since *time.Time `json:"since,omitempty"`
+	// RunningFor is how long a pending job had been running when the status was fetched
+	RunningFor string `json:"runningFor,omitempty"`
+}
+
+// StreamHealth is the acceptance history of the last payloads of a release stream
+type StreamHealth struct {
+	Stream string `json:"stream"`
+	// Payloads is the number of payloads looked at, newest first
+	Payloads int `json:"payloads"`
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	// Other counts the payloads neither accepted nor rejected, e.g. Ready or Failed
+	Other int `json:"other"`
+	// AcceptanceRate is the percentage of the accepted and rejected payloads which were accepted
+	AcceptanceRate float64 `json:"acceptanceRate"`
+	// LongestRejectionStreak is the largest number of payloads rejected in a row
+	LongestRejectionStreak int `json:"longestRejectionStreak"`
+	// CurrentRejectionStreak is the number of payloads rejected since the last accepted one
+	CurrentRejectionStreak int        `json:"currentRejectionStreak"`
+	LastAccepted           string     `json:"lastAccepted,omitempty"`
+	LastAcceptedAt         *time.Time `json:"lastAcceptedAt,omitempty"`
+	// SinceLastAccepted is how long ago the last accepted payload was created when the
+	// health was computed
+	SinceLastAccepted string `json:"sinceLastAccepted,omitempty"`
+	// Jobs are the pass rates of the verification jobs, the least reliable first
+	Jobs []JobPassRate `json:"jobs"`
+	// Unavailable are the payloads whose verification results could not be fetched
+	Unavailable []UnavailablePayload `json:"unavailable,omitempty"`
+}
+
+// UnavailablePayload is a payload whose verification results could not be fetched
+type UnavailablePayload struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// JobPassRate is how often a verification job passed over several payloads
+type JobPassRate struct {
+	Name string  `json:"name"`
+	Kind JobKind `json:"kind"`
+	// Runs counts the finished runs, pending ones are left out
+	Runs     int     `json:"runs"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	PassRate float64 `json:"passRate"`
+}
+
+// JobMatrix is the state of every verification job in the last payloads of a release
+// stream, the view of the release controller stream page
+type JobMatrix struct {
+	Stream string `json:"stream"`
+	// Payloads are the columns of the matrix, newest first
+	Payloads []MatrixPayload `json:"payloads"`
+	// Jobs are the rows of the matrix, blocking jobs first
+	Jobs []MatrixJob `json:"jobs"`
+}
+
+// MatrixPayload is a column of a job matrix
+type MatrixPayload struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	// Available is false if the verification results of the payload could not be fetched
+	Available bool `json:"available"`
+	// Error is why the verification results could not be fetched
+	Error string `json:"error,omitempty"`
+}
+
+// MatrixJob is a row of a job matrix
+type MatrixJob struct {
+	Name string  `json:"name"`
+	Kind JobKind `json:"kind"`
+	// Cells has one cell per payload, in the same order, with an empty state where the job did not run
+	Cells []MatrixCell `json:"cells"`
+	// FailingSince is the oldest payload of the failures the job has had in a row, if its latest run failed
+	FailingSince string `json:"failingSince,omitempty"`
+}
+
+// MatrixCell is the state of a job in a payload
+type MatrixCell struct {
+	State   string `json:"state,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Retries int    `json:"retries,omitempty"`
+}
+
+// JobBisection is where the current failure streak of a verification job started
+type JobBisection struct {
+	Stream string  `json:"stream"`
+	Job    string  `json:"job"`
+	Kind   JobKind `json:"kind"`
+	// Failures is the number of payloads the job failed in since it last passed
+	Failures       int    `json:"failures"`
+	FirstFailed    string `json:"firstFailed"`
+	FirstFailedURL string `json:"firstFailedURL,omitempty"`
+	// LastPassed is empty if the job did not pass in any of the payloads looked at
+	LastPassed    string `json:"lastPassed,omitempty"`
+	LastPassedURL string `json:"lastPassedURL,omitempty"`
+	// Suspects are the changes from the last payload the job passed in to the first one it failed in
+	Suspects *ReleaseComparison `json:"suspects,omitempty"`
+	// Unavailable are the payloads whose verification results could not be fetched, the
+	// job may have started failing in one of them
+	Unavailable []UnavailablePayload `json:"unavailable,omitempty"`
+}
+
+// ReleaseComparison is what changed between two releases
+type ReleaseComparison struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Components are the components whose version changed, e.g. Kubernetes or CoreOS
+	Components    []ComponentChange `json:"components"`
+	NewImages     []string          `json:"newImages"`
+	RemovedImages []string          `json:"removedImages"`
+	UpdatedImages []ImageChange     `json:"updatedImages"`
+	// Commits are the commits of the updated images, without duplicates
+	Commits []Commit `json:"commits"`
+	// Issues are the issues referenced by the commits, bugs and CVEs included
+	Issues []Issue `json:"issues"`
+}
+
+// ComponentChange is the version change of a component between two releases
+type ComponentChange struct {
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	// DiffURL shows the changes between the versions, if the release controller knows it
+	DiffURL string `json:"diffURL,omitempty"`
+}
+
+// ImageChange is an image of the payload rebuilt from new commits
+type ImageChange struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+	// FullChangeLog is the URL of the full list of commits, when some are left out
+	FullChangeLog string `json:"fullChangeLog,omitempty"`
+}
+
+// Commit is a commit merged in an image of the payload
+type Commit struct {
+	Image   string   `json:"image"`
+	Subject string   `json:"subject"`
+	URL     string   `json:"url,omitempty"`
+	Issues  []string `json:"issues,omitempty"`
+}
+
+// ReleaseUpgrades are the upgrades tested into and out of a release
+type ReleaseUpgrades struct {
+	Release string `json:"release"`
+	// To are the upgrades from other releases to this one
+	To []UpgradeEdge `json:"to"`
+	// From are the upgrades from this release to other ones
+	From []UpgradeEdge `json:"from"`
+}
+
+// UpgradeEdge is an upgrade path between two releases with the results of its test runs
+type UpgradeEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Success int    `json:"success"`
+	Failure int    `json:"failure"`
+	// Total counts the runs, including the pending ones
+	Total int `json:"total"`
+	// SuccessRate is the percentage of the finished runs which succeeded
+	SuccessRate float64      `json:"successRate"`
+	Runs        []UpgradeRun `json:"runs"`
+}
+
+// UpgradeRun is a run of an upgrade job
+type UpgradeRun struct {
+	State string `json:"state"`
+	URL   string `json:"url"`
+}
+
+// TestResults lists tests reported by the test step of a Prow job
+type TestResults struct {
+	// Job is the name of the Prow job
+	Job string `json:"job"`
+	// Test is the name of the CI test, e.g. e2e-aws-ovn
+	Test string `json:"test"`
+	// Step is the name of the step which ran the tests
+	Step  string   `json:"step"`
+	Tests []string `json:"tests"`
+	// Lines are the lines of the log reporting the tests, as printed by openshift-tests
+	Lines []string `json:"lines,omitempty"`
+}
+
+// JobLog is a log, or an excerpt of a log, of a Prow job
+type JobLog struct {
+	// Job is the name of the Prow job
+	Job string `json:"job"`
+	// Step is the name of the failed step the log belongs to, if known
+	Step string `json:"step,omitempty"`
+	// Path is the artifact path the log was read from, relative to the job root
+	Path string `json:"path"`
+	Log  string `json:"log"`
+}
+
+// RiskAnalysis is the risk analysis computed by the test step of a Prow job, as
+// written to risk-analysis.json
+type RiskAnalysis struct {
+	Job  string          `json:"job"`
+	Data json.RawMessage `json:"data"`
+}
+
+// SpyglassEvent is an error or warning interval recorded by the e2e monitor of a job
+type SpyglassEvent struct {
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	// Test is the e2e test the event was recorded for, empty if it is not tied to a test
+	Test    string    `json:"test,omitempty"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
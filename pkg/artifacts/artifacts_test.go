@@ -0,0 +1,222 @@
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/cache"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/httpclient"
+)
+
+var testTree = map[string]string{
+	"build-log.txt": "Step e2e-aws-gather-extra failed after 1m",
+	"prowjob.json":  `{"spec":{"job":"periodic-ci-openshift-release-master-nightly-4.20-e2e-aws"}}`,
+	"artifacts/e2e-aws/gather-extra/artifacts/pods.json":  `{"items":[]}`,
+	"artifacts/e2e-aws/gather-extra/artifacts/nodes.json": `{"items":[]}`,
+}
+
+func writeTree(t *testing.T, root string) {
+	t.Helper()
+	for name, content := range testTree {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeTarball(t *testing.T, archive, prefix string) {
+	t.Helper()
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range testTree {
+		hdr := &tar.Header{Name: prefix + name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkSource(t *testing.T, src ArtifactSource) {
+	t.Helper()
+	if got := src.JobName(); got != "periodic-ci-openshift-release-master-nightly-4.20-e2e-aws" {
+		t.Errorf("unexpected job name %q", got)
+	}
+	data, err := src.Fetch(context.Background(), "artifacts/e2e-aws/gather-extra/artifacts//pods.json")
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if data != `{"items":[]}` {
+		t.Errorf("unexpected content %q", data)
+	}
+	if _, err := src.Fetch(context.Background(), "artifacts/missing.txt"); err == nil {
+		t.Errorf("expected an error fetching a missing file")
+	}
+	names, err := src.List(context.Background(), "artifacts/e2e-aws/gather-extra/artifacts/")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"nodes.json", "pods.json"}) {
+		t.Errorf("unexpected listing %v", names)
+	}
+	names, err = src.List(context.Background(), "artifacts")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"e2e-aws/"}) {
+		t.Errorf("unexpected listing %v", names)
+	}
+}
+
+func TestDirSource(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root)
+	src, err := NewArtifactSource(context.Background(), root, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSource(t, src)
+	if _, err := src.Fetch(context.Background(), "../../etc/passwd"); err == nil {
+		t.Errorf("expected paths outside the root to be rejected")
+	}
+}
+
+func TestTarballSource(t *testing.T) {
+	for _, prefix := range []string{"", "1234567890/", "./logs/job/1234567890/"} {
+		t.Run(prefix, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "job.tar.gz")
+			writeTarball(t, archive, prefix)
+			src, err := NewArtifactSource(context.Background(), archive, config.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSource(t, src)
+		})
+	}
+}
+
+func TestGCSSource(t *testing.T) {
+	for _, jobURL := range []string{
+		"https://prow.ci.openshift.org/view/gs/test-platform-results/logs/periodic-ci-openshift-release-master-nightly-4.20-e2e-aws/1234567890",
+		"https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/test-platform-results/logs/periodic-ci-openshift-release-master-nightly-4.20-e2e-aws/1234567890/",
+	} {
+		src, err := NewArtifactSource(context.Background(), jobURL, config.Default())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := src.JobName(); got != "periodic-ci-openshift-release-master-nightly-4.20-e2e-aws" {
+			t.Errorf("unexpected job name %q", got)
+		}
+	}
+	if _, err := NewArtifactSource(context.Background(), "https://example.com/not/a/prow/job", config.Default()); err == nil {
+		t.Errorf("expected an error for a non Prow URL")
+	}
+}
+
+func TestTarballSourceIndexedOnce(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "job.tar.gz")
+	// a name longer than 100 characters is stored in an extended header
+	long := "artifacts/e2e-aws/gather-extra/artifacts/" + strings.Repeat("x", 100) + ".json"
+	testTree[long] = "long"
+	defer delete(testTree, long)
+	writeTarball(t, archive, "")
+	first, err := NewArtifactSource(context.Background(), archive, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := first.Fetch(context.Background(), long); err != nil || data != "long" {
+		t.Errorf("unexpected content of %s: %q %v", long, data, err)
+	}
+	second, err := NewArtifactSource(context.Background(), archive, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("expected the source of an unchanged archive to be reused")
+	}
+
+	delete(testTree, long)
+	writeTarball(t, archive, "")
+	if err := os.Chtimes(archive, time.Time{}, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	third, err := NewArtifactSource(context.Background(), archive, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == first {
+		t.Errorf("expected a changed archive to be indexed again")
+	}
+	checkSource(t, third)
+}
+
+func TestGCSSourceRunningJob(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if path.Base(r.URL.Path) == "finished.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("log"))
+	}))
+	defer server.Close()
+	c, err := cache.New(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := httpclient.Default()
+	options := httpclient.DefaultOptions()
+	options.Cache = c
+	httpclient.SetDefault(httpclient.New(options))
+	defer httpclient.SetDefault(previous)
+	cfg := config.Default()
+	cfg.Prow = []config.Prow{{Name: "test", URL: server.URL, GCSWeb: server.URL, Storage: server.URL}}
+
+	// every tool call uses its own source
+	for range 2 {
+		src, err := NewArtifactSource(context.Background(), server.URL+"/view/gs/bucket/logs/job/1", cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range []string{"build-log.txt", "build-log.txt", "prowjob.json"} {
+			if data, err := src.Fetch(context.Background(), file); err != nil || data != "log" {
+				t.Fatalf("unexpected content of %s: %q %v", file, data, err)
+			}
+		}
+	}
+	if n := requests["/bucket/logs/job/1/finished.json"]; n != 1 {
+		t.Errorf("expected finished.json to be looked for once while the job runs, got %d requests", n)
+	}
+	if n := requests["/bucket/logs/job/1/build-log.txt"]; n != 4 {
+		t.Errorf("expected the artifacts of a running job not to be cached, got %d requests", n)
+	}
+}
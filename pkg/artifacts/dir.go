@@ -0,0 +1,104 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// dirSource reads the artifacts of a Prow job from a local directory, e.g. one
+// previously downloaded with gsutil, laid out the same way as the GCS bucket.
+type dirSource struct {
+	root    string
+	jobName string
+}
+
+func (d *dirSource) Fetch(ctx context.Context, file string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(d.resolve(file))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (d *dirSource) List(ctx context.Context, dir string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(d.resolve(dir))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (d *dirSource) JobName() string {
+	return d.jobName
+}
+
+func (d *dirSource) Location() string {
+	return d.root
+}
+
+// resolve maps a path relative to the job root onto the local filesystem,
+// without allowing it to escape the root.
+func (d *dirSource) resolve(file string) string {
+	return filepath.Join(d.root, filepath.FromSlash(filepath.Clean("/"+file)))
+}
+
+var buildIDRegex = regexp.MustCompile(`^\d+$`)
+
+// prowJob is the subset of prowjob.json needed to identify the job
+type prowJob struct {
+	Spec struct {
+		Job string `json:"job"`
+	} `json:"spec"`
+}
+
+// jobNameFromProwJob extracts the job name from the contents of prowjob.json
+func jobNameFromProwJob(data []byte) string {
+	var pj prowJob
+	if err := json.Unmarshal(data, &pj); err != nil {
+		return ""
+	}
+	return pj.Spec.Job
+}
+
+// jobNameFromPath guesses the job name from a path shaped like .../<job>/<build id>
+func jobNameFromPath(p string) string {
+	base := filepath.Base(p)
+	if buildIDRegex.MatchString(base) {
+		return filepath.Base(filepath.Dir(p))
+	}
+	return base
+}
+
+func newDirSource(root string) (*dirSource, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact directory %s: %w", root, err)
+	}
+	d := &dirSource{root: root}
+	if data, err := os.ReadFile(filepath.Join(root, "prowjob.json")); err == nil {
+		d.jobName = jobNameFromProwJob(data)
+	}
+	if d.jobName == "" {
+		d.jobName = jobNameFromPath(strings.TrimSuffix(root, string(filepath.Separator)))
+	}
+	return d, nil
+}
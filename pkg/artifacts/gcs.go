@@ -0,0 +1,131 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/httpclient"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// runningTTL is how long a job without finished.json is known to be running, before
+// finished.json is looked for again
+const runningTTL = time.Minute
+
+// gcsSource reads the artifacts of a Prow job from GCS over HTTP. Files are
+// downloaded from the storage API when the Prow instance has one configured and
+// through gcsweb otherwise, directories are always listed through gcsweb.
+// Once the job has finished its artifacts never change, so they are cached
+// for good.
+type gcsSource struct {
+	prowurl string
+	prow    *config.Prow
+	// root is the bucket and path of the job, e.g. test-platform-results/logs/<job>/<id>
+	root string
+
+	mu sync.Mutex
+	// finished is nil until the job has been looked at
+	finished *bool
+}
+
+func (g *gcsSource) Fetch(ctx context.Context, file string) (string, error) {
+	data, err := g.get(ctx, g.fileURL(file))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (g *gcsSource) List(ctx context.Context, dir string) ([]string, error) {
+	data, err := g.get(ctx, fmt.Sprintf("%s/gcs/%s/", g.prow.GCSWeb, path.Join(g.root, dir)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+
+	// gcsweb renders directories as an HTML page with one link per entry
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	var names []string
+	doc.Find("a").Each(func(i int, s *goquery.Selection) {
+		name := strings.TrimSpace(s.Text())
+		if name == "" || name == ".." || strings.HasPrefix(name, "gs://") {
+			return
+		}
+		names = append(names, name)
+	})
+	return names, nil
+}
+
+func (g *gcsSource) fileURL(file string) string {
+	if g.prow.Storage != "" {
+		return fmt.Sprintf("%s/%s", g.prow.Storage, path.Join(g.root, file))
+	}
+	return fmt.Sprintf("%s/gcs/%s", g.prow.GCSWeb, path.Join(g.root, file))
+}
+
+// get fetches url through the cache if the job has finished
+func (g *gcsSource) get(ctx context.Context, url string) ([]byte, error) {
+	if g.isFinished(ctx) {
+		return httpclient.Default().GetImmutable(ctx, url)
+	}
+	return httpclient.Default().Get(ctx, url)
+}
+
+// isFinished reports whether the job has uploaded finished.json. It is decided once
+// per source, and a running job is remembered in the cache for runningTTL so that the
+// sources of the following calls don't look for finished.json again.
+func (g *gcsSource) isFinished(ctx context.Context) bool {
+	c := httpclient.Default().Cache()
+	if c == nil {
+		return false
+	}
+	g.mu.Lock()
+	finished := g.finished
+	g.mu.Unlock()
+	if finished != nil {
+		return *finished
+	}
+
+	url := g.fileURL("finished.json")
+	runningKey := "running:" + url
+	done := false
+	if _, running := c.Get(runningKey); !running {
+		_, err := httpclient.Default().GetImmutable(ctx, url)
+		done = err == nil
+		if httpclient.IsNotFound(err) {
+			_ = c.Put(runningKey, nil, runningTTL)
+		}
+	}
+	g.mu.Lock()
+	g.finished = &done
+	g.mu.Unlock()
+	return done
+}
+
+func (g *gcsSource) JobName() string {
+	parts := strings.Split(g.root, "/")
+	if len(parts) < 2 {
+		return g.root
+	}
+	return parts[len(parts)-2]
+}
+
+func (g *gcsSource) Location() string {
+	return g.prowurl
+}
+
+func newGCSSource(prowurl string, cfg *config.Config) (*gcsSource, error) {
+	prow, root, ok := cfg.ProwForURL(prowurl)
+	if !ok || root == "" {
+		return nil, fmt.Errorf("invalid Prow job URL: %s", prowurl)
+	}
+	return &gcsSource{prowurl: prowurl, prow: prow, root: root}, nil
+}
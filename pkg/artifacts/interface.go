@@ -0,0 +1,52 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+)
+
+// ArtifactSource provides access to the artifacts tree of a single Prow job run.
+// All paths are relative to the root of the job, e.g. "build-log.txt" or
+// "artifacts/e2e-aws/gather-extra/artifacts/pods.json".
+type ArtifactSource interface {
+	// Fetch returns the contents of the file at the given path
+	Fetch(ctx context.Context, path string) (string, error)
+	// List returns the names of the entries in the given directory, directories have a trailing slash
+	List(ctx context.Context, dir string) ([]string, error)
+	// JobName returns the name of the Prow job the artifacts belong to
+	JobName() string
+	// Location returns a human readable description of where the artifacts are read from
+	Location() string
+}
+
+// NewArtifactSource returns the ArtifactSource for the given location, which can be
+// a job URL of one of the configured Prow instances, a local directory laid out
+// like a Prow artifacts tree or a .tar.gz archive of such a tree.
+func NewArtifactSource(ctx context.Context, location string, cfg *config.Config) (ArtifactSource, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return nil, fmt.Errorf("empty artifact location")
+	}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return newGCSSource(location, cfg)
+	}
+	if isTarball(location) {
+		return newTarballSource(ctx, location)
+	}
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact location %s: %w", location, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid artifact location %s: not a directory or .tar.gz archive", location)
+	}
+	return newDirSource(location)
+}
+
+func isTarball(location string) bool {
+	return strings.HasSuffix(location, ".tar.gz") || strings.HasSuffix(location, ".tgz")
+}
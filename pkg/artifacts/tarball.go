@@ -0,0 +1,283 @@
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tarballSource reads the artifacts of a Prow job from a .tar.gz archive of a
+// Prow artifacts tree. The archive may contain the tree at its top level or
+// nested in a single directory such as <build id>/.
+type tarballSource struct {
+	archive string
+	// prefix is the path inside the archive where the job root lives
+	prefix string
+	// files holds every regular file in the archive, relative to the job root
+	files []string
+	// entries locates the contents of the files in the decompressed archive
+	entries map[string]tarballEntry
+	jobName string
+	// modTime and size identify the version of the archive the index was built from
+	modTime time.Time
+	size    int64
+}
+
+// tarballEntry is the position of the contents of a file in the decompressed archive
+type tarballEntry struct {
+	offset int64
+	size   int64
+}
+
+// tarballs memoizes the sources by archive, so that the archive is only indexed once
+var tarballs = struct {
+	sync.Mutex
+	sources map[string]*tarballSource
+}{sources: map[string]*tarballSource{}}
+
+func (t *tarballSource) Fetch(ctx context.Context, file string) (string, error) {
+	entry, ok := t.entries[cleanRelative(file)]
+	if !ok {
+		return "", fmt.Errorf("%s not found in %s: %w", file, t.archive, os.ErrNotExist)
+	}
+	var content string
+	err := t.read(ctx, func(r io.Reader) error {
+		// gzip can't seek, skip the decompressed data before the entry
+		if _, err := io.CopyN(io.Discard, r, entry.offset); err != nil {
+			return err
+		}
+		data, err := io.ReadAll(io.LimitReader(r, entry.size))
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) != entry.size {
+			return io.ErrUnexpectedEOF
+		}
+		content = string(data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error reading %s from %s: %w", file, t.archive, err)
+	}
+	return content, nil
+}
+
+func (t *tarballSource) List(ctx context.Context, dir string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dir = cleanRelative(dir)
+	if dir != "" {
+		dir += "/"
+	}
+	seen := map[string]bool{}
+	for _, file := range t.files {
+		if !strings.HasPrefix(file, dir) {
+			continue
+		}
+		rest := strings.TrimPrefix(file, dir)
+		if i := strings.Index(rest, "/"); i >= 0 {
+			rest = rest[:i+1]
+		}
+		seen[rest] = true
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("%s not found in %s: %w", dir, t.archive, os.ErrNotExist)
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (t *tarballSource) JobName() string {
+	return t.jobName
+}
+
+func (t *tarballSource) Location() string {
+	return t.archive
+}
+
+// read calls fn with the decompressed archive, which stops being readable once ctx is done
+func (t *tarballSource) read(ctx context.Context, fn func(r io.Reader) error) error {
+	f, err := os.Open(t.archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	return fn(&contextReader{ctx: ctx, r: gz})
+}
+
+// walk calls fn for every entry of the archive with the offset of its contents in the
+// decompressed archive, until fn returns an error or ctx is done
+func (t *tarballSource) walk(ctx context.Context, fn func(hdr *tar.Header, offset int64, r io.Reader) error) error {
+	err := t.read(ctx, func(r io.Reader) error {
+		counter := &countingReader{r: r}
+		tr := tar.NewReader(counter)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// tar reads whole blocks without buffering, so after the header
+			// the counter is at the start of the contents
+			if err := fn(hdr, counter.n, tr); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", t.archive, err)
+	}
+	return nil
+}
+
+// contextReader fails the reads once ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// countingReader counts the bytes read
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// cleanRelative normalizes an archive or artifact path to the form "a/b/c"
+func cleanRelative(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// findRoot returns the shallowest directory containing a top-level Prow artifact
+func findRoot(files []string) string {
+	root := ""
+	depth := -1
+	for _, file := range files {
+		base := path.Base(file)
+		if base != "build-log.txt" && base != "prowjob.json" && base != "finished.json" {
+			continue
+		}
+		dir := path.Dir(file)
+		d := strings.Count(dir, "/")
+		if dir == "." {
+			return ""
+		}
+		if depth < 0 || d < depth {
+			root, depth = dir+"/", d
+		}
+	}
+	return root
+}
+
+// newTarballSource returns the source of the archive, indexing it unless it was
+// already indexed and hasn't changed since
+func newTarballSource(ctx context.Context, archive string) (*tarballSource, error) {
+	key, err := filepath.Abs(archive)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(archive)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact location %s: %w", archive, err)
+	}
+	tarballs.Lock()
+	t, ok := tarballs.sources[key]
+	tarballs.Unlock()
+	if ok && t.archive == archive && t.modTime.Equal(info.ModTime()) && t.size == info.Size() {
+		return t, nil
+	}
+	t, err = indexTarball(ctx, archive, info)
+	if err != nil {
+		return nil, err
+	}
+	tarballs.Lock()
+	tarballs.sources[key] = t
+	tarballs.Unlock()
+	return t, nil
+}
+
+// indexTarball reads the archive once, recording where the contents of every file are
+func indexTarball(ctx context.Context, archive string, info os.FileInfo) (*tarballSource, error) {
+	t := &tarballSource{archive: archive, modTime: info.ModTime(), size: info.Size()}
+	var all []string
+	entries := map[string]tarballEntry{}
+	prowJobs := map[string][]byte{}
+	err := t.walk(ctx, func(hdr *tar.Header, offset int64, r io.Reader) error {
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+		name := cleanRelative(hdr.Name)
+		if _, ok := entries[name]; ok {
+			return nil
+		}
+		all = append(all, name)
+		entries[name] = tarballEntry{offset: offset, size: hdr.Size}
+		if path.Base(name) == "prowjob.json" {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			prowJobs[name] = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no files found in %s", archive)
+	}
+	t.prefix = findRoot(all)
+	t.entries = map[string]tarballEntry{}
+	for _, name := range all {
+		if strings.HasPrefix(name, t.prefix) {
+			file := strings.TrimPrefix(name, t.prefix)
+			t.files = append(t.files, file)
+			t.entries[file] = entries[name]
+		}
+	}
+	if data, ok := prowJobs[t.prefix+"prowjob.json"]; ok {
+		t.jobName = jobNameFromProwJob(data)
+	}
+	if t.jobName == "" {
+		// fall back to the archive name, treating it as the parent of the tree
+		base := filepath.Base(archive)
+		base = strings.TrimSuffix(strings.TrimSuffix(base, ".tgz"), ".tar.gz")
+		t.jobName = jobNameFromPath(path.Join(base, t.prefix))
+	}
+	return t, nil
+}
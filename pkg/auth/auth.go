@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Principal is an authenticated client of the network transports
+type Principal struct {
+	// Name identifies the client in errors and logs
+	Name string
+	// ID identifies the credential of the client, token:<name> or cert:<common name>,
+	// as a token and a certificate can have the same name
+	ID string
+	// Tools lists the tools the client may call, all of them if empty
+	Tools []string
+}
+
+// Allows tells whether the principal may call a tool
+func (p *Principal) Allows(tool string) bool {
+	return len(p.Tools) == 0 || slices.Contains(p.Tools, "*") || slices.Contains(p.Tools, tool)
+}
+
+// Token is a bearer token accepted by the server
+type Token struct {
+	// Name identifies the holder of the token
+	Name string `mapstructure:"name"`
+	// Token is the secret sent in the Authorization header
+	Token string `mapstructure:"token"`
+	// TokenEnv is the environment variable holding the secret, instead of Token
+	TokenEnv string `mapstructure:"tokenEnv"`
+	// Tools lists the tools the holder may call, all of them if empty
+	Tools []string `mapstructure:"tools"`
+}
+
+// ClientCertificate grants tools to the TLS clients presenting a certificate with a common name
+type ClientCertificate struct {
+	// CommonName is the subject common name of the client certificate
+	CommonName string `mapstructure:"commonName"`
+	// Tools lists the tools the client may call, all of them if empty
+	Tools []string `mapstructure:"tools"`
+}
+
+// Options configures the authentication of the network transports
+type Options struct {
+	// TokenFile is a YAML or JSON file listing the tokens and client certificates
+	TokenFile string
+	// Token is a single token allowed to call every tool
+	Token string
+	// CertFile and KeyFile are the certificate and key used to serve TLS
+	CertFile, KeyFile string
+	// ClientCAFile is the CA bundle used to verify client certificates. Setting it
+	// requires every client to present a certificate signed by one of these CAs.
+	ClientCAFile string
+}
+
+// Authenticator authenticates the requests to the network transports
+type Authenticator struct {
+	tokens             []Token
+	clientCertificates []ClientCertificate
+	tlsConfig          *tls.Config
+}
+
+// defaultTokenName is the name of the token passed with --auth-token
+const defaultTokenName = "default"
+
+// New returns the authenticator configured by the options, which lets every request
+// through if neither tokens nor client certificates are configured
+func New(opts Options) (*Authenticator, error) {
+	a := &Authenticator{}
+	if opts.TokenFile != "" {
+		if err := a.loadTokenFile(opts.TokenFile); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Token != "" {
+		if slices.ContainsFunc(a.tokens, func(token Token) bool { return token.Name == defaultTokenName }) {
+			return nil, fmt.Errorf("token %s in %s has the name of the --auth-token token", defaultTokenName, opts.TokenFile)
+		}
+		a.tokens = append(a.tokens, Token{Name: defaultTokenName, Token: opts.Token})
+	}
+	if err := a.loadTLS(opts); err != nil {
+		return nil, err
+	}
+	if len(a.clientCertificates) > 0 && (a.tlsConfig == nil || a.tlsConfig.ClientCAs == nil) {
+		return nil, fmt.Errorf("client certificates are listed in %s but no client CA is configured", opts.TokenFile)
+	}
+	return a, nil
+}
+
+// loadTokenFile reads the tokens and client certificates from a file
+func (a *Authenticator) loadTokenFile(path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("error reading token file %s: %w", path, err)
+	}
+	if err := v.UnmarshalKey("tokens", &a.tokens); err != nil {
+		return fmt.Errorf("error parsing tokens in %s: %w", path, err)
+	}
+	if err := v.UnmarshalKey("clientCertificates", &a.clientCertificates); err != nil {
+		return fmt.Errorf("error parsing clientCertificates in %s: %w", path, err)
+	}
+	names := map[string]bool{}
+	for i := range a.tokens {
+		token := &a.tokens[i]
+		if token.Name == "" {
+			return fmt.Errorf("token %d in %s has no name", i, path)
+		}
+		if names[token.Name] {
+			return fmt.Errorf("token %s in %s is listed twice", token.Name, path)
+		}
+		names[token.Name] = true
+		if token.TokenEnv != "" {
+			token.Token = os.Getenv(token.TokenEnv)
+		}
+		if token.Token == "" {
+			return fmt.Errorf("token %s in %s is empty", token.Name, path)
+		}
+	}
+	commonNames := map[string]bool{}
+	for i, cert := range a.clientCertificates {
+		if cert.CommonName == "" {
+			return fmt.Errorf("client certificate %d in %s has no commonName", i, path)
+		}
+		if commonNames[cert.CommonName] {
+			return fmt.Errorf("client certificate %s in %s is listed twice", cert.CommonName, path)
+		}
+		commonNames[cert.CommonName] = true
+	}
+	return nil
+}
+
+// loadTLS builds the TLS configuration of the server, if a certificate is configured
+func (a *Authenticator) loadTLS(opts Options) error {
+	if opts.CertFile == "" && opts.KeyFile == "" {
+		if opts.ClientCAFile != "" {
+			return fmt.Errorf("client certificate authentication requires a TLS certificate and key")
+		}
+		return nil
+	}
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return fmt.Errorf("both a TLS certificate and key are required")
+	}
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+	a.tlsConfig = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("error reading client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in client CA file %s", opts.ClientCAFile)
+		}
+		a.tlsConfig.ClientCAs = pool
+		a.tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return nil
+}
+
+// TLSConfig returns the configuration to serve TLS with, nil to serve plain HTTP
+func (a *Authenticator) TLSConfig() *tls.Config {
+	return a.tlsConfig
+}
+
+// Enabled tells whether the requests are authenticated
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0 || a.tlsConfig != nil && a.tlsConfig.ClientCAs != nil
+}
+
+// Middleware authenticates the requests before passing them to next, with the
+// principal of the client in their context
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, status := a.authenticate(r)
+		if principal == nil {
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
+	})
+}
+
+// authenticate returns the principal of a request, or the HTTP status to fail it with.
+// When tokens are configured a valid token is required, and its allowlist applies
+// even if the client also presented a certificate.
+func (a *Authenticator) authenticate(r *http.Request) (*Principal, int) {
+	if len(a.tokens) > 0 {
+		scheme, secret, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || secret == "" {
+			return nil, http.StatusUnauthorized
+		}
+		for _, token := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(token.Token), []byte(strings.TrimSpace(secret))) == 1 {
+				return &Principal{Name: token.Name, ID: "token:" + token.Name, Tools: token.Tools}, http.StatusOK
+			}
+		}
+		return nil, http.StatusUnauthorized
+	}
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, http.StatusUnauthorized
+	}
+	commonName := r.TLS.PeerCertificates[0].Subject.CommonName
+	if len(a.clientCertificates) == 0 {
+		return &Principal{Name: commonName, ID: "cert:" + commonName}, http.StatusOK
+	}
+	for _, cert := range a.clientCertificates {
+		if cert.CommonName == commonName {
+			return &Principal{Name: commonName, ID: "cert:" + commonName, Tools: cert.Tools}, http.StatusOK
+		}
+	}
+	return nil, http.StatusForbidden
+}
+
+type principalKey struct{}
+
+// NewContext returns a context carrying the principal of a request
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of a request, if it was authenticated
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
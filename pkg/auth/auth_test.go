@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// whoami answers with the name of the principal of the request
+var whoami = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	principal, _ := FromContext(r.Context())
+	_, _ = w.Write([]byte(principal.Name))
+})
+
+func TestTokens(t *testing.T) {
+	t.Setenv("TEAM_TOKEN", "team-secret")
+	file := filepath.Join(t.TempDir(), "tokens.yaml")
+	err := os.WriteFile(file, []byte(`
+tokens:
+  - name: bot
+    token: bot-secret
+    tools: [latest_accepted_release]
+  - name: team
+    tokenEnv: TEAM_TOKEN
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(Options{TokenFile: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := a.Middleware(whoami)
+	for _, tc := range []struct {
+		header string
+		status int
+		name   string
+	}{
+		{"", http.StatusUnauthorized, ""},
+		{"Bearer wrong", http.StatusUnauthorized, ""},
+		{"Basic bot-secret", http.StatusUnauthorized, ""},
+		{"Bearer bot-secret", http.StatusOK, "bot"},
+		{"bearer team-secret", http.StatusOK, "team"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Errorf("%q: expected status %d, got %d", tc.header, tc.status, rec.Code)
+		}
+		if tc.status == http.StatusOK && rec.Body.String() != tc.name {
+			t.Errorf("%q: expected principal %s, got %s", tc.header, tc.name, rec.Body.String())
+		}
+	}
+
+	bot := &Principal{Name: "bot", Tools: []string{"latest_accepted_release"}}
+	if !bot.Allows("latest_accepted_release") || bot.Allows("get_cache_stats") {
+		t.Errorf("unexpected allowlist of %v", bot.Tools)
+	}
+	if team := (&Principal{Name: "team"}); !team.Allows("get_cache_stats") {
+		t.Errorf("expected a principal without allowlist to be allowed every tool")
+	}
+}
+
+func TestClientCertificates(t *testing.T) {
+	a := &Authenticator{
+		tlsConfig:          &tls.Config{ClientCAs: x509.NewCertPool()},
+		clientCertificates: []ClientCertificate{{CommonName: "alice", Tools: []string{"*"}}},
+	}
+	handler := a.Middleware(whoami)
+	for _, tc := range []struct {
+		commonName string
+		status     int
+	}{
+		{"", http.StatusUnauthorized},
+		{"alice", http.StatusOK},
+		{"mallory", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tc.commonName != "" {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: tc.commonName}}}}
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Errorf("%q: expected status %d, got %d", tc.commonName, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	a, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Enabled() || a.TLSConfig() != nil {
+		t.Errorf("expected authentication and TLS to be disabled without options")
+	}
+	if _, err := New(Options{ClientCAFile: "ca.crt"}); err == nil {
+		t.Errorf("expected a client CA without certificate to be rejected")
+	}
+}
+
+func TestUniqueNames(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"tokens.yaml":  "tokens:\n  - name: bot\n    token: one\n  - name: bot\n    token: two\n",
+		"certs.yaml":   "clientCertificates:\n  - commonName: alice\n  - commonName: alice\n",
+		"default.yaml": "tokens:\n  - name: default\n    token: one\n",
+	} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := New(Options{TokenFile: file, Token: "two"}); err == nil {
+			t.Errorf("%s: expected names listed twice to be rejected", name)
+		}
+	}
+
+	// a token and a certificate with the same name are different credentials
+	a := &Authenticator{tokens: []Token{{Name: "alice", Token: "secret"}}}
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	token, _ := a.authenticate(req)
+	a = &Authenticator{tlsConfig: &tls.Config{ClientCAs: x509.NewCertPool()}}
+	req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}}}
+	cert, _ := a.authenticate(req)
+	if token == nil || cert == nil || token.ID != "token:alice" || cert.ID != "cert:alice" {
+		t.Errorf("unexpected principals %+v %+v", token, cert)
+	}
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Forever is the TTL of entries which never expire, e.g. artifacts of finished jobs
+const Forever time.Duration = -1
+
+// tmpPrefix marks entries which are still being written
+const tmpPrefix = ".tmp-"
+
+// Stats reports the activity of a Cache since it was opened
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	Size      int64
+	MaxSize   int64
+	Dir       string
+}
+
+// Cache is a size bounded on-disk cache of HTTP response bodies keyed by URL.
+// Every entry is a file named after the hash of its key, holding the expiry
+// time on the first line followed by the data. The modification time of the
+// file is bumped on every hit and the least recently used entries are evicted
+// once the cache grows past its maximum size.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu   sync.Mutex
+	size int64
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+// New opens the cache stored in dir, creating it if needed. Entries are evicted
+// once their total size exceeds maxSize bytes.
+func New(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory %s: %w", dir, err)
+	}
+	c := &Cache{dir: dir, maxSize: maxSize}
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		c.size += e.size
+	}
+	c.evict()
+	return c, nil
+}
+
+// Get returns the data stored for key, if it is present and has not expired
+func (c *Cache) Get(key string) ([]byte, bool) {
+	file := c.path(key)
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	header, data, ok := bytes.Cut(raw, []byte("\n"))
+	expiry, err := strconv.ParseInt(string(header), 10, 64)
+	if !ok || err != nil || (expiry != 0 && time.Now().UnixNano() > expiry) {
+		c.remove(file)
+		c.misses.Add(1)
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(file, now, now)
+	c.hits.Add(1)
+	return data, true
+}
+
+// Put stores data for key, it expires after ttl unless ttl is Forever
+func (c *Cache) Put(key string, data []byte, ttl time.Duration) error {
+	var expiry int64
+	if ttl != Forever {
+		expiry = time.Now().Add(ttl).UnixNano()
+	}
+	header := strconv.FormatInt(expiry, 10) + "\n"
+	size := int64(len(header) + len(data))
+	if size > c.maxSize {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, tmpPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("error creating cache entry: %w", err)
+	}
+	w := bufio.NewWriter(tmp)
+	_, _ = w.WriteString(header)
+	_, _ = w.Write(data)
+	err = w.Flush()
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("error writing cache entry: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	file := c.path(key)
+	var previous int64
+	if info, err := os.Stat(file); err == nil {
+		previous = info.Size()
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("error writing cache entry: %w", err)
+	}
+	c.size += size - previous
+	c.evict()
+	return nil
+}
+
+// Clear removes every entry from the cache
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error clearing cache: %w", err)
+		}
+	}
+	c.size = 0
+	return nil
+}
+
+// Stats returns the hit, miss and eviction counts along with the current size of the cache
+func (c *Cache) Stats() Stats {
+	entries, _ := c.entries()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   len(entries),
+		Size:      c.size,
+		MaxSize:   c.maxSize,
+		Dir:       c.dir,
+	}
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+func (c *Cache) remove(file string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info, err := os.Stat(file)
+	if err != nil {
+		return
+	}
+	if os.Remove(file) == nil {
+		c.size -= info.Size()
+	}
+}
+
+// evict removes the least recently used entries until the cache fits in maxSize,
+// it must be called with mu held
+func (c *Cache) evict() {
+	if c.size <= c.maxSize {
+		return
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+	for _, e := range entries {
+		if c.size <= c.maxSize {
+			return
+		}
+		if os.Remove(e.path) == nil {
+			c.size -= e.size
+			c.evictions.Add(1)
+		}
+	}
+}
+
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries lists the cache entries, removing temporary files left behind by a crash
+func (c *Cache) entries() ([]entry, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache directory %s: %w", c.dir, err)
+	}
+	var entries []entry
+	for _, d := range dirEntries {
+		if d.IsDir() {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		file := filepath.Join(c.dir, d.Name())
+		if strings.HasPrefix(d.Name(), tmpPrefix) {
+			if time.Since(info.ModTime()) > time.Hour {
+				_ = os.Remove(file)
+			}
+			continue
+		}
+		if len(d.Name()) != 2*sha256.Size {
+			// not one of ours, leave it alone
+			continue
+		}
+		entries = append(entries, entry{path: file, size: info.Size(), modTime: info.ModTime()})
+	}
+	return entries, nil
+}
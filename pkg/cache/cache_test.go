@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetPut(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("https://example.com/a"); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	if err := c.Put("https://example.com/a", []byte("a\nb"), Forever); err != nil {
+		t.Fatal(err)
+	}
+	data, ok := c.Get("https://example.com/a")
+	if !ok || string(data) != "a\nb" {
+		t.Errorf("unexpected entry %q %t", data, ok)
+	}
+	if err := c.Put("https://example.com/b", []byte("b"), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("https://example.com/b"); ok {
+		t.Error("expected the expired entry to be a miss")
+	}
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Entries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("0123456789012345678901234")
+	for _, key := range []string{"a", "b"} {
+		if err := c.Put(key, data, Forever); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// make a the most recently used entry
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(c.path("a"), past, past)
+	_ = os.Chtimes(c.path("b"), past.Add(-time.Hour), past.Add(-time.Hour))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a hit")
+	}
+	if err := c.Put("c", data, Forever); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected the most recently used entry to be kept")
+	}
+	if stats := c.Stats(); stats.Evictions != 1 || stats.Size > 64 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// unrelated files in the directory are never touched
+	other := filepath.Join(dir, "README")
+	if err := os.WriteFile(other, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("expected %s to survive Clear: %v", other, err)
+	}
+	if stats := c.Stats(); stats.Entries != 0 || stats.Size != 0 {
+		t.Errorf("unexpected stats after Clear %+v", stats)
+	}
+}
@@ -1,318 +1,320 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
 	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/utils"
 	configv1 "github.com/openshift/api/config/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type clusterCli struct {
+	config *config.Config
+	memo   jobMemo
 }
 
-func (c *clusterCli) GetPodsInState(prowurl string, state string) (string, error) {
-	//Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetPodsInState(ctx context.Context, prowurl string, state string) ([]api.PodSummary, error) {
+	// Download the pods.json file from the job artifacts
+	pods, err := c.pods(ctx, prowurl)
 	if err != nil {
-		return "", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the pods.json file from the artifact URL
-	pods, err := utils.LoadPodsFromFile(artifactURL + "pods.json")
-	if err != nil {
-		return "", fmt.Errorf("error loading pods: %w", err)
-	}
-	var result string
 	switch state {
 	case "CrashLoopBackOff":
-		result = utils.CrashLoopBackOffSummary(pods)
+		return utils.CrashLoopBackOffSummary(pods), nil
 	case "Pending":
-		result = utils.PendingPodsSummary(pods)
+		return utils.PendingPodsSummary(pods), nil
 	case "Init":
-		result = utils.InitStateSummary(pods)
+		return utils.InitStateSummary(pods), nil
 	case "Error":
-		result = utils.ErrorStateSummary(pods)
+		return utils.ErrorStateSummary(pods), nil
 	case "Running":
-		result = utils.RunningPodsSummary(pods)
+		return utils.RunningPodsSummary(pods), nil
 	default:
-		result = utils.AllPodsSummary(pods)
+		return utils.AllPodsSummary(pods), nil
 	}
-	return result, nil
 }
 
-func (c *clusterCli) GetPodsInNamespace(prowurl string, namespace string) (string, error) {
-	//Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
-	if err != nil {
-		return "No pods found", fmt.Errorf("error getting gather extra folder path: %w", err)
-	}
-	// Download the pods.json file from the artifact URL
-	pods, err := utils.LoadPodsFromFile(artifactURL + "pods.json")
+func (c *clusterCli) GetPodsInNamespace(ctx context.Context, prowurl string, namespace string) ([]api.PodSummary, error) {
+	pods, err := c.pods(ctx, prowurl)
 	if err != nil {
-		return "No pods found", fmt.Errorf("error loading pods: %w", err)
+		return nil, err
 	}
-	return utils.FilterPodsByNamespaceAsString(pods, namespace), nil
+	return utils.FilterPodsByNamespace(pods, namespace), nil
 }
 
-func (c *clusterCli) GetPodsInNode(prowurl string, nodeName string) (string, error) {
-	//Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetPodsInNode(ctx context.Context, prowurl string, nodeName string) ([]api.PodSummary, error) {
+	pods, err := c.pods(ctx, prowurl)
 	if err != nil {
-		return "No pods found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the pods.json file from the artifact URL
-	pods, err := utils.LoadPodsFromFile(artifactURL + "pods.json")
-	if err != nil {
-		return "No pods found", fmt.Errorf("error loading pods: %w", err)
-	}
-	return utils.FilterPodsByNodeAsString(pods, nodeName), nil
+	return utils.FilterPodsByNode(pods, nodeName), nil
 }
 
-func (c *clusterCli) GetContainersInPod(prowurl string, podName string, namespace string) (string, error) {
-	//Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetContainersInPod(ctx context.Context, prowurl string, podName string, namespace string) (*api.PodContainers, error) {
+	pods, err := c.pods(ctx, prowurl)
 	if err != nil {
-		return "No containers found", fmt.Errorf("error getting gather extra folder path: %w", err)
-	}
-	// Download the pods.json file from the artifact URL
-	pods, err := utils.LoadPodsFromFile(artifactURL + "pods.json")
-	if err != nil {
-		return "No containers found", fmt.Errorf("error loading pods: %w", err)
+		return nil, err
 	}
 	// Filter pods by namespace
 	nspods := utils.GetPodsByNamespace(pods, namespace)
 	if len(nspods) == 0 {
-		return "No pods found", fmt.Errorf("no pods found in namespace %s", namespace)
+		return nil, fmt.Errorf("no pods found in namespace %s", namespace)
 	}
 	// Get container names in the specified pod
-	return utils.GetContainerNamesInPod(pods, podName), nil
+	return utils.GetContainerNamesInPod(nspods, podName)
 }
 
-func (c *clusterCli) GetContainerLogs(prowurl string, podName string, namespace string, containerName string) (string, error) {
-	//Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetContainerLogs(ctx context.Context, prowurl string, podName string, namespace string, containerName string) (string, error) {
+	src, artifactPath, err := c.gatherExtra(ctx, prowurl)
 	if err != nil {
-		return "No container logs found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return "", err
 	}
 	// Construct the path to the container log file
-	logFilePath := utils.GetContainerLogFilePath(artifactURL, podName, namespace, containerName)
-	// Download the container log file from the artifact URL
-	logData, err := utils.FetchURL(logFilePath)
+	logFilePath := utils.GetContainerLogFilePath(artifactPath, podName, namespace, containerName)
+	// Download the container log file from the job artifacts
+	logData, err := src.Fetch(ctx, logFilePath)
 	if err != nil {
-		return "No container logs found", fmt.Errorf("error fetching container log: %w", err)
+		return "", fmt.Errorf("error fetching container log: %w", err)
 	}
 	return logData, nil
 }
 
 // Extract status summary (Available, Progressing, Degraded) for each operator
-func (c *clusterCli) GetClusterOperatorStatusSummary(prowurl string) (string, error) {
-	//Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetClusterOperatorStatusSummary(ctx context.Context, prowurl string) ([]api.OperatorStatus, error) {
+	src, artifactPath, err := c.gatherExtra(ctx, prowurl)
 	if err != nil {
-		return "No clusteroperatrs found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the clusteroperators.json file from the artifact URL
-	operators, err := utils.LoadClusterOperatorsFromFile(artifactURL + "clusteroperators.json")
+	// Download the clusteroperators.json file from the job artifacts
+	operators, err := c.loadClusterOperators(ctx, src, artifactPath+"clusteroperators.json")
 	if err != nil {
-		return "No clusteroperatrs found", fmt.Errorf("error loading cluster operators: %w", err)
+		return nil, fmt.Errorf("error loading cluster operators: %w", err)
 	}
-	var b strings.Builder
+	statuses := []api.OperatorStatus{}
 	for _, op := range operators {
-		name := op.Name
-		var available, progressing, degraded string
-
+		status := api.OperatorStatus{Name: op.Name}
 		for _, cond := range op.Status.Conditions {
+			condition := &api.Condition{
+				Type:    string(cond.Type),
+				Status:  string(cond.Status),
+				Reason:  cond.Reason,
+				Message: cond.Message,
+			}
+			if !cond.LastTransitionTime.IsZero() {
+				condition.LastTransitionTime = &cond.LastTransitionTime.Time
+			}
 			switch cond.Type {
 			case configv1.OperatorAvailable:
-				available = fmt.Sprintf("%s (Reason: %s)", cond.Status, cond.Reason)
+				status.Available = condition
 			case configv1.OperatorProgressing:
-				progressing = fmt.Sprintf("%s (Reason: %s)", cond.Status, cond.Reason)
+				status.Progressing = condition
 			case configv1.OperatorDegraded:
-				degraded = fmt.Sprintf("%s (Reason: %s)", cond.Status, cond.Reason)
+				status.Degraded = condition
 			}
 		}
-
-		fmt.Fprintf(&b, "Operator: %s\n  Available: %s\n  Progressing: %s\n  Degraded: %s\n\n",
-			name, available, progressing, degraded)
+		statuses = append(statuses, status)
 	}
-	return b.String(), nil
+	return statuses, nil
 }
 
-func (c *clusterCli) GetClusterVersionSummary(prowurl string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetClusterVersionSummary(ctx context.Context, prowurl string) (*api.ClusterVersionSummary, error) {
+	src, artifactPath, err := c.gatherExtra(ctx, prowurl)
 	if err != nil {
-		return "No clusterversion object found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the clusterversion.json file from the artifact URL
-	clusterVersion, err := utils.LoadClusterVersionFromFile(artifactURL + "clusterversion.json")
+	// Download the clusterversion.json file from the job artifacts
+	clusterVersion, err := c.loadClusterVersion(ctx, src, artifactPath+"clusterversion.json")
 	if err != nil {
-		return "No clusterversion object found", fmt.Errorf("error loading cluster version: %w", err)
+		return nil, fmt.Errorf("error loading cluster version: %w", err)
 	}
 
-	var b strings.Builder
-	fmt.Fprintf(&b, "Cluster Version: %s\n", clusterVersion.Status.Desired.Version)
-	fmt.Fprintf(&b, "Desired Image: %s\n", clusterVersion.Status.Desired.Image)
-	fmt.Fprintf(&b, "Desired URL: %s\n", clusterVersion.Status.Desired.URL)
-	fmt.Fprintf(&b, "Available Updates: %d\n", len(clusterVersion.Status.AvailableUpdates))
+	summary := &api.ClusterVersionSummary{
+		Version: clusterVersion.Status.Desired.Version,
+		Image:   clusterVersion.Status.Desired.Image,
+		URL:     string(clusterVersion.Status.Desired.URL),
+	}
 	for _, update := range clusterVersion.Status.AvailableUpdates {
-		fmt.Fprintf(&b, "  - %s\n", update.Version)
+		summary.AvailableUpdates = append(summary.AvailableUpdates, update.Version)
 	}
-
-	fmt.Fprintf(&b, "\nUpdate History:\n")
 	for _, hist := range clusterVersion.Status.History {
-		fmt.Fprintf(&b, "  - Version: %s | State: %s | Verified: %t\n    Image: %s\n",
-			hist.Version, hist.State, hist.Verified, hist.Image)
-		fmt.Fprintf(&b, "    Started: %s | Completed: %s\n",
-			hist.StartedTime.Time.Format("2006-01-02 15:04:05"),
-			func(t *metav1.Time) string {
-				if t == nil {
-					return "N/A"
-				}
-				return t.Time.Format("2006-01-02 15:04:05")
-			}(hist.CompletionTime))
-		if hist.AcceptedRisks != "" {
-			fmt.Fprintf(&b, "    Accepted Risks:\n    %s\n", utils.IndentMultiline(hist.AcceptedRisks, "    "))
+		entry := api.UpdateHistory{
+			Version:       hist.Version,
+			State:         string(hist.State),
+			Verified:      hist.Verified,
+			Image:         hist.Image,
+			Started:       hist.StartedTime.Time,
+			AcceptedRisks: hist.AcceptedRisks,
+		}
+		if hist.CompletionTime != nil {
+			entry.Completed = &hist.CompletionTime.Time
 		}
+		summary.History = append(summary.History, entry)
 	}
-	return b.String(), nil
+	return summary, nil
 }
 
-func (c *clusterCli) GetNodesInfo(prowurl string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
-	if err != nil {
-		return "No nodes found", fmt.Errorf("error getting gather extra folder path: %w", err)
-	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+func (c *clusterCli) GetNodesInfo(ctx context.Context, prowurl string) ([]api.NodeInfo, error) {
+	nodes, err := c.nodes(ctx, prowurl)
 	if err != nil {
-		return "No nodes found", fmt.Errorf("error loading nodes: %w", err)
+		return nil, err
 	}
-	var b strings.Builder
+	infos := []api.NodeInfo{}
 	for _, node := range nodes {
-		nodeInfo := utils.GetNodeInfoString(&node)
-		if nodeInfo == "" {
-			nodeInfo = fmt.Sprintf("Node %s has no NodeInfo available", node.Name)
-		}
-		fmt.Fprintf(&b, "Node: %s\n%s\n\n", node.Name, nodeInfo)
+		infos = append(infos, utils.GetNodeInfo(&node))
 	}
-	return b.String(), nil
+	return infos, nil
 }
 
-func (c *clusterCli) GetNodesLabels(prowurl string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
-	if err != nil {
-		return "No node labels found", fmt.Errorf("error getting gather extra folder path: %w", err)
-	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+func (c *clusterCli) GetNodesLabels(ctx context.Context, prowurl string) ([]api.NodeLabels, error) {
+	nodes, err := c.nodes(ctx, prowurl)
 	if err != nil {
-		return "No node labels found", fmt.Errorf("error loading nodes: %w", err)
+		return nil, err
 	}
-	// Get the labels for all nodes using the GetNodeLabelsString function
-	var b strings.Builder
+	labels := []api.NodeLabels{}
 	for _, node := range nodes {
-		b.WriteString(utils.GetNodeLabelsString(&node))
+		labels = append(labels, utils.GetNodeLabels(&node))
 	}
-	return b.String(), nil
+	return labels, nil
 }
 
-func (c *clusterCli) GetNodeInfoByName(prowurl string, nodeName string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetNodeInfoByName(ctx context.Context, prowurl string, nodeName string) (*api.NodeInfo, error) {
+	node, err := c.node(ctx, prowurl, nodeName)
 	if err != nil {
-		return "Node not found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+	info := utils.GetNodeInfo(node)
+	return &info, nil
+}
+
+func (c *clusterCli) GetNodeLabelsByName(ctx context.Context, prowurl string, nodeName string) (*api.NodeLabels, error) {
+	node, err := c.node(ctx, prowurl, nodeName)
 	if err != nil {
-		return "Node not found", fmt.Errorf("error loading nodes: %w", err)
+		return nil, err
 	}
-	// Find the node by name
-	node, err := utils.FindNodeByName(nodes, nodeName)
+	labels := utils.GetNodeLabels(node)
+	return &labels, nil
+}
+
+func (c *clusterCli) GetNodesAnnotations(ctx context.Context, prowurl string) ([]api.NodeAnnotations, error) {
+	nodes, err := c.nodes(ctx, prowurl)
 	if err != nil {
-		return "Node not found", fmt.Errorf("error finding node: %w", err)
+		return nil, err
 	}
-	return utils.GetNodeInfoString(node), nil
+	annotations := []api.NodeAnnotations{}
+	for _, node := range nodes {
+		annotations = append(annotations, utils.GetNodeAnnotations(&node))
+	}
+	return annotations, nil
 }
 
-func (c *clusterCli) GetNodeLabelsByName(prowurl string, nodeName string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+func (c *clusterCli) GetNodeAnnotationsByName(ctx context.Context, prowurl string, nodeName string) (*api.NodeAnnotations, error) {
+	node, err := c.node(ctx, prowurl, nodeName)
 	if err != nil {
-		return "Node labels not found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+	annotations := utils.GetNodeAnnotations(node)
+	return &annotations, nil
+}
+
+func (c *clusterCli) GetNodesConditions(ctx context.Context, prowurl string) ([]api.NodeConditions, error) {
+	nodes, err := c.nodes(ctx, prowurl)
 	if err != nil {
-		return "Node labels not found", fmt.Errorf("error loading nodes: %w", err)
+		return nil, err
 	}
-	// Find the node by name
-	node, err := utils.FindNodeByName(nodes, nodeName)
-	if err != nil {
-		return "Node labels not found", fmt.Errorf("error finding node: %w", err)
+	conditions := []api.NodeConditions{}
+	for _, node := range nodes {
+		conditions = append(conditions, utils.GetNodeConditions(&node))
 	}
-	return utils.GetNodeLabelsString(node), nil
+	return conditions, nil
 }
 
-func (c *clusterCli) GetNodesAnnotations(prowurl string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+// gatherExtra opens the job artifacts and finds the gather-extra folder
+func (c *clusterCli) gatherExtra(ctx context.Context, prowurl string) (artifacts.ArtifactSource, string, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, c.config)
 	if err != nil {
-		return "No node annotations found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, "", fmt.Errorf("error opening job artifacts: %w", err)
 	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+	artifactPath, err := utils.GetGatherExtraFolderPath(src)
 	if err != nil {
-		return "No node annotations found", fmt.Errorf("error loading nodes: %w", err)
+		return nil, "", fmt.Errorf("error getting gather extra folder path: %w", err)
 	}
-	var b strings.Builder
-	for _, node := range nodes {
-		b.WriteString(utils.GetNodeAnnotationsString(&node))
-	}
-	return b.String(), nil
+	return src, artifactPath, nil
 }
 
-func (c *clusterCli) GetNodeAnnotationsByName(prowurl string, nodeName string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+// pods returns the pods gathered at the end of the job
+func (c *clusterCli) pods(ctx context.Context, prowurl string) ([]corev1.Pod, error) {
+	src, artifactPath, err := c.gatherExtra(ctx, prowurl)
 	if err != nil {
-		return "Node annotations not found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+	pods, err := c.loadPods(ctx, src, artifactPath+"pods.json")
 	if err != nil {
-		return "Node annotations not found", fmt.Errorf("error loading nodes: %w", err)
+		return nil, fmt.Errorf("error loading pods: %w", err)
 	}
-	// Find the node by name
-	node, err := utils.FindNodeByName(nodes, nodeName)
+	return pods, nil
+}
+
+// nodes returns the nodes gathered at the end of the job
+func (c *clusterCli) nodes(ctx context.Context, prowurl string) ([]corev1.Node, error) {
+	src, artifactPath, err := c.gatherExtra(ctx, prowurl)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.loadNodes(ctx, src, artifactPath+"nodes.json")
 	if err != nil {
-		return "Node annotations not found", fmt.Errorf("error finding node: %w", err)
+		return nil, fmt.Errorf("error loading nodes: %w", err)
 	}
-	return utils.GetNodeAnnotationsString(node), nil
+	return nodes, nil
 }
 
-func (c *clusterCli) GetNodesConditions(prowurl string) (string, error) {
-	// Fetch the url of the extra folder
-	artifactURL, err := utils.GetGatherExtraFolderPath(prowurl)
+// node returns the named node gathered at the end of the job
+func (c *clusterCli) node(ctx context.Context, prowurl, nodeName string) (*corev1.Node, error) {
+	nodes, err := c.nodes(ctx, prowurl)
 	if err != nil {
-		return "No node conditions found", fmt.Errorf("error getting gather extra folder path: %w", err)
+		return nil, err
 	}
-	// Download the nodes.json file from the artifact URL
-	nodes, err := utils.LoadNodesFromFile(artifactURL + "nodes.json")
+	node, err := utils.FindNodeByName(nodes, nodeName)
 	if err != nil {
-		return "No node conditions found", fmt.Errorf("error loading nodes: %w", err)
-	}
-	var b strings.Builder
-	for _, node := range nodes {
-		b.WriteString(utils.GetNodeConditionsString(&node))
+		return nil, fmt.Errorf("error finding node: %w", err)
 	}
-	return b.String(), nil
+	return node, nil
+}
+
+// loadPods returns the pods listed in the artifact at path, parsing it only once per job
+func (c *clusterCli) loadPods(ctx context.Context, src artifacts.ArtifactSource, path string) ([]corev1.Pod, error) {
+	return memoize(ctx, &c.memo, src.Location(), path, func(ctx context.Context) ([]corev1.Pod, error) {
+		return utils.LoadPodsFromFile(ctx, src, path)
+	})
+}
+
+// loadNodes returns the nodes listed in the artifact at path, parsing it only once per job
+func (c *clusterCli) loadNodes(ctx context.Context, src artifacts.ArtifactSource, path string) ([]corev1.Node, error) {
+	return memoize(ctx, &c.memo, src.Location(), path, func(ctx context.Context) ([]corev1.Node, error) {
+		return utils.LoadNodesFromFile(ctx, src, path)
+	})
+}
+
+// loadClusterOperators returns the cluster operators listed in the artifact at path, parsing it only once per job
+func (c *clusterCli) loadClusterOperators(ctx context.Context, src artifacts.ArtifactSource, path string) ([]configv1.ClusterOperator, error) {
+	return memoize(ctx, &c.memo, src.Location(), path, func(ctx context.Context) ([]configv1.ClusterOperator, error) {
+		return utils.LoadClusterOperatorsFromFile(ctx, src, path)
+	})
 }
 
-func newClusterCli() *clusterCli {
-	return &clusterCli{}
+// loadClusterVersion returns the cluster version in the artifact at path, parsing it only once per job
+func (c *clusterCli) loadClusterVersion(ctx context.Context, src artifacts.ArtifactSource, path string) (*configv1.ClusterVersion, error) {
+	return memoize(ctx, &c.memo, src.Location(), path, func(ctx context.Context) (*configv1.ClusterVersion, error) {
+		return utils.LoadClusterVersionFromFile(ctx, src, path)
+	})
+}
+
+func newClusterCli(cfg *config.Config) *clusterCli {
+	return &clusterCli{
+		config: cfg,
+	}
 }
@@ -1,37 +1,45 @@
 package cluster
 
-// ReleaseController interface
+import (
+	"context"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+)
+
+// Cluster interface. Every method takes the location of the job artifacts, which is
+// either a Prow job URL or a local artifact root (directory or .tar.gz archive).
 type Cluster interface {
-	// GetPodsInState returns the pods in a specific state
-	GetPodsInState(prowurl string, state string) (string, error)
+	// GetPodsInState returns the pods in a specific state: CrashLoopBackOff, Pending, Init, Error, Running or All
+	GetPodsInState(ctx context.Context, prowurl string, state string) ([]api.PodSummary, error)
 	// GetPodsInNamespace returns the pods in a specific namespace
-	GetPodsInNamespace(prowurl string, namespace string) (string, error)
+	GetPodsInNamespace(ctx context.Context, prowurl string, namespace string) ([]api.PodSummary, error)
 	// GetPodsInNode returns the pods in a specific node
-	GetPodsInNode(prowurl string, nodeName string) (string, error)
+	GetPodsInNode(ctx context.Context, prowurl string, nodeName string) ([]api.PodSummary, error)
 	// GetContainersInPod returns the containers in a specific pod
-	GetContainersInPod(prowurl string, podName string, namespace string) (string, error)
+	GetContainersInPod(ctx context.Context, prowurl string, podName string, namespace string) (*api.PodContainers, error)
 	// GetContainerLogs returns the logs of a specific container in a pod
-	GetContainerLogs(prowurl string, podName string, namespace string, containerName string) (string, error)
+	GetContainerLogs(ctx context.Context, prowurl string, podName string, namespace string, containerName string) (string, error)
 	// GetClusterOperatorStatusSummary returns the status summary of cluster operators
-	GetClusterOperatorStatusSummary(prowurl string) (string, error)
+	GetClusterOperatorStatusSummary(ctx context.Context, prowurl string) ([]api.OperatorStatus, error)
 	// GetClusterVersionSummary returns the cluster version summary
-	GetClusterVersionSummary(prowurl string) (string, error)
+	GetClusterVersionSummary(ctx context.Context, prowurl string) (*api.ClusterVersionSummary, error)
 	// GetNodesInfo returns the information of all nodes in the cluster
-	GetNodesInfo(prowurl string) (string, error)
+	GetNodesInfo(ctx context.Context, prowurl string) ([]api.NodeInfo, error)
 	// GetNodeInfoByName returns the information of a specific node by name
-	GetNodeInfoByName(prowurl string, nodeName string) (string, error)
+	GetNodeInfoByName(ctx context.Context, prowurl string, nodeName string) (*api.NodeInfo, error)
 	// GetNodeLabelsByName returns the labels of a specific node by name
-	GetNodeLabelsByName(prowurl string, nodeName string) (string, error)
+	GetNodeLabelsByName(ctx context.Context, prowurl string, nodeName string) (*api.NodeLabels, error)
 	// GetNodeAnnotationsByName returns the annotations of a specific node by name
-	GetNodeAnnotationsByName(prowurl string, nodeName string) (string, error)
-	// GetNodesLabels returns all labels from all nodes in the cluster as a string
-	GetNodesLabels(prowurl string) (string, error)
-	// GetNodesAnnotations returns all annotations from all nodes in the cluster as a string
-	GetNodesAnnotations(prowurl string) (string, error)
-	// GetNodesConditions returns all conditions from all nodes in the cluster as a string
-	GetNodesConditions(prowurl string) (string, error)
+	GetNodeAnnotationsByName(ctx context.Context, prowurl string, nodeName string) (*api.NodeAnnotations, error)
+	// GetNodesLabels returns all labels from all nodes in the cluster
+	GetNodesLabels(ctx context.Context, prowurl string) ([]api.NodeLabels, error)
+	// GetNodesAnnotations returns all annotations from all nodes in the cluster
+	GetNodesAnnotations(ctx context.Context, prowurl string) ([]api.NodeAnnotations, error)
+	// GetNodesConditions returns all conditions from all nodes in the cluster
+	GetNodesConditions(ctx context.Context, prowurl string) ([]api.NodeConditions, error)
 }
 
-func NewCluster() Cluster {
-	return newClusterCli()
+func NewCluster(cfg *config.Config) Cluster {
+	return newClusterCli(cfg)
 }
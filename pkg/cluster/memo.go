@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// maxMemoJobs is the number of jobs whose parsed artifacts are kept in memory
+const maxMemoJobs = 8
+
+// memoValue is an object parsed from an artifact, or being parsed
+type memoValue struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// jobMemo keeps the objects parsed from the artifacts of the most recently used
+// jobs, so the tools asking about the same job share one parsed PodList or
+// NodeList instead of unmarshalling it on every call.
+type jobMemo struct {
+	mu sync.Mutex
+	// jobs maps the location of a job to its parsed artifacts, keyed by path
+	jobs map[string]map[string]*memoValue
+	// recent holds the locations of the jobs, most recently used last
+	recent []string
+}
+
+// touch marks the job as the most recently used one, forgetting the least
+// recently used job if there are too many. It must be called with mu held.
+func (m *jobMemo) touch(job string) map[string]*memoValue {
+	if m.jobs == nil {
+		m.jobs = map[string]map[string]*memoValue{}
+	}
+	for i, j := range m.recent {
+		if j == job {
+			m.recent = append(m.recent[:i], m.recent[i+1:]...)
+			break
+		}
+	}
+	m.recent = append(m.recent, job)
+	if len(m.recent) > maxMemoJobs {
+		delete(m.jobs, m.recent[0])
+		m.recent = m.recent[1:]
+	}
+	values, ok := m.jobs[job]
+	if !ok {
+		values = map[string]*memoValue{}
+		m.jobs[job] = values
+	}
+	return values
+}
+
+// forget drops a failed value so that the next caller tries again
+func (m *jobMemo) forget(job, path string, v *memoValue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if values, ok := m.jobs[job]; ok && values[path] == v {
+		delete(values, path)
+	}
+}
+
+// memoize returns the object parsed from the artifact at path of the given job,
+// calling load only if no other caller has loaded it or is loading it already.
+// The returned object is shared and must not be modified.
+func memoize[T any](ctx context.Context, m *jobMemo, job, path string, load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	for {
+		m.mu.Lock()
+		values := m.touch(job)
+		v, ok := values[path]
+		if !ok {
+			v = &memoValue{done: make(chan struct{})}
+			values[path] = v
+		}
+		m.mu.Unlock()
+
+		if !ok {
+			v.value, v.err = load(ctx)
+			if v.err != nil {
+				m.forget(job, path, v)
+			}
+			close(v.done)
+		}
+
+		select {
+		case <-v.done:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+		if v.err == nil {
+			return v.value.(T), nil
+		}
+		// the caller which loaded the value gave up, try again with our own context
+		if ok && ctx.Err() == nil && (errors.Is(v.err, context.Canceled) || errors.Is(v.err, context.DeadlineExceeded)) {
+			continue
+		}
+		return zero, v.err
+	}
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/viper"
+)
+
+// ReleaseController describes a release controller the server can talk to
+type ReleaseController struct {
+	// Name is the short name used to refer to the release controller, e.g. "ocp"
+	Name string `mapstructure:"name"`
+	// URL is the base URL of the release controller, e.g. https://amd64.ocp.releases.ci.openshift.org
+	URL string `mapstructure:"url"`
+	// Product is the product whose payloads the release controller builds, e.g. "ocp" or "okd"
+	Product string `mapstructure:"product"`
+	// Architecture is the architecture of the payloads, "multi" for the multi-arch payloads
+	Architecture string `mapstructure:"architecture"`
+	// Aliases are other names the release controller can be referred to with, e.g. "aarch64"
+	Aliases []string `mapstructure:"aliases"`
+}
+
+// Host returns the host name of the release controller
+func (rc ReleaseController) Host() string {
+	return hostOf(rc.URL)
+}
+
+// Prow describes a Prow front-end (deck) and the hosts serving its job artifacts
+type Prow struct {
+	// Name is the short name of the Prow instance, e.g. "ci"
+	Name string `mapstructure:"name"`
+	// URL is the base URL of deck, job URLs look like <URL>/view/gs/<bucket>/<path>
+	URL string `mapstructure:"url"`
+	// GCSWeb is the base URL of the gcsweb instance used to browse the artifacts
+	GCSWeb string `mapstructure:"gcsweb"`
+	// Storage is the base URL used to download artifact files. If empty, files are
+	// downloaded through GCSWeb, which is needed for buckets that are not public.
+	Storage string `mapstructure:"storage"`
+}
+
+// Config is the endpoint registry of the server
+type Config struct {
+	ReleaseControllers []ReleaseController `mapstructure:"releaseControllers"`
+	Prow               []Prow              `mapstructure:"prow"`
+	// AllowUnregisteredHosts lets tools reach release controllers which are not in the
+	// registry by passing their host or URL. Off by default, as it lets any caller
+	// make the server send requests to any host.
+	AllowUnregisteredHosts bool `mapstructure:"allowUnregisteredHosts"`
+}
+
+// Default returns the registry of the public OpenShift CI endpoints
+func Default() *Config {
+	return &Config{
+		ReleaseControllers: []ReleaseController{
+			{Name: "okd", URL: "https://amd64.origin.releases.ci.openshift.org", Product: "okd", Architecture: "amd64", Aliases: []string{"origin"}},
+			{Name: "ocp", URL: "https://amd64.ocp.releases.ci.openshift.org", Product: "ocp", Architecture: "amd64", Aliases: []string{"openshift", "amd64", "x86_64"}},
+			{Name: "multi", URL: "https://multi.ocp.releases.ci.openshift.org", Product: "ocp", Architecture: "multi", Aliases: []string{"multi-arch", "heterogeneous"}},
+			{Name: "arm64", URL: "https://arm64.ocp.releases.ci.openshift.org", Product: "ocp", Architecture: "arm64", Aliases: []string{"arm", "aarch64"}},
+			{Name: "ppc64le", URL: "https://ppc64le.ocp.releases.ci.openshift.org", Product: "ocp", Architecture: "ppc64le", Aliases: []string{"ppc", "ppc64", "power"}},
+			{Name: "s390x", URL: "https://s390x.ocp.releases.ci.openshift.org", Product: "ocp", Architecture: "s390x", Aliases: []string{"s390", "z", "ibmz"}},
+		},
+		Prow: []Prow{
+			{
+				Name:    "ci",
+				URL:     "https://prow.ci.openshift.org",
+				GCSWeb:  "https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com",
+				Storage: "https://storage.googleapis.com",
+			},
+		},
+	}
+}
+
+// Load reads the registry from the viper configuration. Sections which are not
+// configured keep their default values.
+func Load(v *viper.Viper) (*Config, error) {
+	cfg := Default()
+	if v.IsSet("releaseControllers") {
+		cfg.ReleaseControllers = nil
+		if err := v.UnmarshalKey("releaseControllers", &cfg.ReleaseControllers); err != nil {
+			return nil, fmt.Errorf("error parsing releaseControllers: %w", err)
+		}
+	}
+	if v.IsSet("prow") {
+		cfg.Prow = nil
+		if err := v.UnmarshalKey("prow", &cfg.Prow); err != nil {
+			return nil, fmt.Errorf("error parsing prow: %w", err)
+		}
+	}
+	cfg.AllowUnregisteredHosts = v.GetBool("allowUnregisteredHosts")
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks that every entry has a name and well-formed URLs
+func (c *Config) Validate() error {
+	names := map[string]string{}
+	for i := range c.ReleaseControllers {
+		rc := &c.ReleaseControllers[i]
+		if rc.Name == "" {
+			return fmt.Errorf("release controller %d has no name", i)
+		}
+		if err := normalizeURL(&rc.URL); err != nil {
+			return fmt.Errorf("release controller %s: %w", rc.Name, err)
+		}
+		for _, name := range append([]string{rc.Name}, rc.Aliases...) {
+			if other, ok := names[strings.ToLower(name)]; ok {
+				return fmt.Errorf("release controller %s: %s is already used by release controller %s", rc.Name, name, other)
+			}
+			names[strings.ToLower(name)] = rc.Name
+		}
+	}
+	for i := range c.Prow {
+		p := &c.Prow[i]
+		if p.Name == "" {
+			return fmt.Errorf("prow instance %d has no name", i)
+		}
+		if err := normalizeURL(&p.URL); err != nil {
+			return fmt.Errorf("prow %s: %w", p.Name, err)
+		}
+		if err := normalizeURL(&p.GCSWeb); err != nil {
+			return fmt.Errorf("prow %s gcsweb: %w", p.Name, err)
+		}
+		if p.Storage != "" {
+			if err := normalizeURL(&p.Storage); err != nil {
+				return fmt.Errorf("prow %s storage: %w", p.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ReleaseControllerHosts returns the hosts of all the configured release controllers
+func (c *Config) ReleaseControllerHosts() []string {
+	var hosts []string
+	for _, rc := range c.ReleaseControllers {
+		hosts = append(hosts, rc.Host())
+	}
+	return hosts
+}
+
+// ReleaseController finds a release controller by name, alias or host
+func (c *Config) ReleaseController(nameOrHost string) (*ReleaseController, bool) {
+	for i, rc := range c.ReleaseControllers {
+		if strings.EqualFold(rc.Name, nameOrHost) || strings.EqualFold(rc.Host(), nameOrHost) || rc.URL == strings.TrimSuffix(nameOrHost, "/") ||
+			slices.ContainsFunc(rc.Aliases, func(alias string) bool { return strings.EqualFold(alias, nameOrHost) }) {
+			return &c.ReleaseControllers[i], true
+		}
+	}
+	return nil, false
+}
+
+// versionPattern matches the OpenShift versions which may qualify a release controller
+// alias, as in "4.20 ppc"
+var versionPattern = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+// Resolve finds the release controller described by a query: its name, host, URL or
+// an alias, or words matching its product and architecture such as "ocp arm64".
+// Versions in the query are ignored, the release controllers serve every version.
+func (c *Config) Resolve(query string) (*ReleaseController, error) {
+	query = strings.TrimSpace(query)
+	if rc, ok := c.ReleaseController(query); ok {
+		return rc, nil
+	}
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool { return unicode.IsSpace(r) || r == ',' || r == '/' }) {
+		if !versionPattern.MatchString(word) {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("no release controller given in %q, expected one of %s", query, strings.Join(c.releaseControllerNames(), ", "))
+	}
+	if len(words) == 1 {
+		if rc, ok := c.ReleaseController(words[0]); ok {
+			return rc, nil
+		}
+	}
+	var matches []*ReleaseController
+	for i, rc := range c.ReleaseControllers {
+		keywords := append([]string{rc.Name, rc.Host(), rc.Product, rc.Architecture}, rc.Aliases...)
+		if !slices.ContainsFunc(words, func(word string) bool {
+			return !slices.ContainsFunc(keywords, func(keyword string) bool { return strings.EqualFold(keyword, word) })
+		}) {
+			matches = append(matches, &c.ReleaseControllers[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no release controller matches %q, expected one of %s", query, strings.Join(c.releaseControllerNames(), ", "))
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, rc := range matches {
+		names = append(names, rc.Name)
+	}
+	return nil, fmt.Errorf("%q matches several release controllers: %s", query, strings.Join(names, ", "))
+}
+
+func (c *Config) releaseControllerNames() []string {
+	var names []string
+	for _, rc := range c.ReleaseControllers {
+		names = append(names, rc.Name)
+	}
+	return names
+}
+
+// ReleaseControllerURL returns the base URL for a release controller name, alias or
+// host. Hosts which are not in the registry are rejected, unless AllowUnregisteredHosts
+// is set, in which case they are assumed to be served over https.
+func (c *Config) ReleaseControllerURL(nameOrHost string) (string, error) {
+	nameOrHost = strings.TrimSuffix(strings.TrimSpace(nameOrHost), "/")
+	rc, err := c.Resolve(nameOrHost)
+	if err == nil {
+		return rc.URL, nil
+	}
+	if !c.AllowUnregisteredHosts {
+		return "", err
+	}
+	if strings.HasPrefix(nameOrHost, "http://") || strings.HasPrefix(nameOrHost, "https://") {
+		return nameOrHost, nil
+	}
+	if strings.Contains(nameOrHost, ".") && !versionPattern.MatchString(nameOrHost) && !strings.ContainsFunc(nameOrHost, unicode.IsSpace) {
+		return "https://" + nameOrHost, nil
+	}
+	return "", err
+}
+
+// ProwForURL finds the Prow instance serving a job URL, which can be either a deck
+// view URL or a gcsweb URL, and returns it together with the bucket path of the job.
+func (c *Config) ProwForURL(jobURL string) (*Prow, string, bool) {
+	for i, p := range c.Prow {
+		for _, prefix := range []string{p.URL + "/view/gs/", p.GCSWeb + "/gcs/"} {
+			if strings.HasPrefix(jobURL, prefix) {
+				return &c.Prow[i], strings.Trim(strings.TrimPrefix(jobURL, prefix), "/"), true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+func normalizeURL(raw *string) error {
+	u, err := url.Parse(strings.TrimSuffix(*raw, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", *raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: expected http(s)://host", *raw)
+	}
+	*raw = u.String()
+	return nil
+}
+
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Host
+}
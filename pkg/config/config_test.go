@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(viper.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for query, want := range map[string]string{
+		"ocp":                                    "https://amd64.ocp.releases.ci.openshift.org",
+		"amd64.origin.releases.ci.openshift.org": "https://amd64.origin.releases.ci.openshift.org",
+		"aarch64":                                "https://arm64.ocp.releases.ci.openshift.org",
+	} {
+		if got, err := cfg.ReleaseControllerURL(query); err != nil || got != want {
+			t.Errorf("unexpected URL for %s: %s %v", query, got, err)
+		}
+	}
+	if _, err := cfg.ReleaseControllerURL("4.20"); err == nil {
+		t.Errorf("expected a version alone to be rejected")
+	}
+	for _, host := range []string{"unknown.example.com", "http://169.254.169.254"} {
+		if got, err := cfg.ReleaseControllerURL(host); err == nil {
+			t.Errorf("expected the unregistered host %s to be rejected, got %s", host, got)
+		}
+	}
+	cfg.AllowUnregisteredHosts = true
+	if got, err := cfg.ReleaseControllerURL("unknown.example.com"); err != nil || got != "https://unknown.example.com" {
+		t.Errorf("expected unregistered hosts to be allowed when opted in, got %s %v", got, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cfg := Default()
+	for query, want := range map[string]string{
+		"okd":          "okd",
+		"Origin":       "okd",
+		"arm":          "arm64",
+		"aarch64":      "arm64",
+		"multi":        "multi",
+		"4.20 ppc":     "ppc64le",
+		"4.19 okd":     "okd",
+		"ocp s390x":    "s390x",
+		"ocp 4.18 arm": "arm64",
+		"https://multi.ocp.releases.ci.openshift.org": "multi",
+	} {
+		rc, err := cfg.Resolve(query)
+		if err != nil {
+			t.Errorf("error resolving %s: %v", query, err)
+		} else if rc.Name != want {
+			t.Errorf("expected %s to resolve to %s, got %s", query, want, rc.Name)
+		}
+	}
+	for _, query := range []string{"", "windows", "okd arm64"} {
+		if rc, err := cfg.Resolve(query); err == nil {
+			t.Errorf("expected %q not to resolve, got %s", query, rc.Name)
+		}
+	}
+	cfg.ReleaseControllers = append(cfg.ReleaseControllers, ReleaseController{Name: "private", URL: "https://releases.example.com", Aliases: []string{"arm"}})
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected a duplicate alias to be rejected")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`
+releaseControllers:
+  - name: private
+    url: https://releases.example.com/
+prow:
+  - name: qe-private
+    url: https://qe-private-deck.example.com
+    gcsweb: https://gcsweb-qe-private.example.com
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.ReleaseControllers) != 1 || cfg.ReleaseControllers[0].URL != "https://releases.example.com" {
+		t.Errorf("unexpected release controllers %+v", cfg.ReleaseControllers)
+	}
+	prow, root, ok := cfg.ProwForURL("https://qe-private-deck.example.com/view/gs/qe-private-deck/logs/job/123")
+	if !ok || prow.Name != "qe-private" || root != "qe-private-deck/logs/job/123" {
+		t.Errorf("unexpected prow match %v %s %t", prow, root, ok)
+	}
+	if _, _, ok := cfg.ProwForURL("https://prow.ci.openshift.org/view/gs/test-platform-results/logs/job/123"); ok {
+		t.Errorf("expected the default prow instance to be replaced")
+	}
+}
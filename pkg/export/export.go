@@ -0,0 +1,87 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasecontroller"
+)
+
+// Export snapshots release controller data to dir as JSON, in the format served by the
+// release controller API so that it can be parsed again for offline analysis:
+//
+//	<dir>/<controller>/<stream>/tags.json    the tags of the stream, newest first
+//	<dir>/<controller>/<stream>/<tag>.json   the APIReleaseInfo of a release
+//
+// The tags of the stream are exported if tag is empty, the release otherwise. It returns
+// the path of the file written.
+func Export(ctx context.Context, rc releasecontroller.ReleaseController, controller, stream, tag, dir string) (string, error) {
+	folder := controller
+	if resolved, err := rc.ResolveReleaseController(ctx, controller); err == nil {
+		folder = resolved.Name
+	}
+	for _, name := range []string{folder, stream, tag} {
+		if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return "", fmt.Errorf("invalid name %q, it cannot be used as a file name", name)
+		}
+	}
+	if stream == "" {
+		return "", fmt.Errorf("a release stream is required")
+	}
+	var content any
+	file := "tags.json"
+	if tag == "" {
+		tags, err := rc.ListReleaseTags(ctx, controller, stream)
+		if err != nil {
+			return "", err
+		}
+		content = api.Release{Name: stream, Tags: tags}
+	} else {
+		info, err := rc.GetReleaseInfo(ctx, controller, stream, tag)
+		if err != nil {
+			return "", err
+		}
+		content, file = info, tag+".json"
+	}
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshaling %s: %w", file, err)
+	}
+	path := filepath.Join(dir, folder, stream, file)
+	if err := writeFile(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeFile writes a file through a temporary file, so that an interrupted export
+// does not leave a truncated snapshot behind
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating export directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
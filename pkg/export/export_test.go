@@ -0,0 +1,64 @@
+package export
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasecontroller"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/utils"
+)
+
+type fakeReleaseController struct {
+	releasecontroller.ReleaseController
+}
+
+func (fakeReleaseController) ResolveReleaseController(ctx context.Context, alias string) (*api.ReleaseController, error) {
+	return &api.ReleaseController{Name: "ocp"}, nil
+}
+
+func (fakeReleaseController) ListReleaseTags(ctx context.Context, releasecontroller, stream string) ([]api.Tag, error) {
+	return []api.Tag{{Name: "4.19.0-0.nightly-2025-05-02-000000", Phase: "Accepted"}}, nil
+}
+
+func (fakeReleaseController) GetReleaseInfo(ctx context.Context, releasecontroller, stream, tag string) (*api.APIReleaseInfo, error) {
+	return &api.APIReleaseInfo{Name: tag, Phase: "Accepted"}, nil
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	path, err := Export(context.Background(), fakeReleaseController{}, "amd64.ocp.releases.ci.openshift.org", "4.19.0-0.nightly", "", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "ocp", "4.19.0-0.nightly", "tags.json") {
+		t.Errorf("unexpected path %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, err := utils.ParseRelease(data)
+	if err != nil || len(release.Tags) != 1 || release.Name != "4.19.0-0.nightly" {
+		t.Errorf("unexpected tags snapshot %+v %v", release, err)
+	}
+
+	path, err = Export(context.Background(), fakeReleaseController{}, "ocp", "4.19.0-0.nightly", "4.19.0-0.nightly-2025-05-02-000000", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := utils.ParseAPIReleaseInfo(data)
+	if err != nil || info.Name != "4.19.0-0.nightly-2025-05-02-000000" {
+		t.Errorf("unexpected release snapshot %+v %v", info, err)
+	}
+
+	if _, err := Export(context.Background(), fakeReleaseController{}, "ocp", "4.19.0-0.nightly", "../escape", dir); err == nil {
+		t.Errorf("expected a tag with a path separator to be rejected")
+	}
+}
@@ -0,0 +1,253 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/cache"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/version"
+)
+
+// Options configures a Client
+type Options struct {
+	// Timeout bounds a single attempt, including reading the response body
+	Timeout time.Duration
+	// ResponseHeaderTimeout bounds the time to wait for the response headers of an attempt
+	ResponseHeaderTimeout time.Duration
+	// Retries is the number of times a failed idempotent request is retried
+	Retries int
+	// MinBackoff is the delay before the first retry, it doubles on every attempt
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+	// UserAgent is sent with every request
+	UserAgent string
+	// Cache stores responses fetched with GetMutable and GetImmutable, nil disables caching
+	Cache *cache.Cache
+	// CacheTTL is how long responses fetched with GetMutable are cached
+	CacheTTL time.Duration
+}
+
+// DefaultOptions returns the options used by the default client
+func DefaultOptions() Options {
+	return Options{
+		Timeout:               5 * time.Minute,
+		ResponseHeaderTimeout: 30 * time.Second,
+		Retries:               3,
+		MinBackoff:            500 * time.Millisecond,
+		MaxBackoff:            10 * time.Second,
+		UserAgent:             fmt.Sprintf("%s/%s", version.BinaryName, version.Version),
+		CacheTTL:              time.Minute,
+	}
+}
+
+// StatusError is returned when the server answers with a non-2xx status code
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("non-OK HTTP status fetching %s: %s", e.URL, e.Status)
+}
+
+// IsNotFound reports whether err is a 404 returned by the server
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// Client is the transport shared by everything which talks to the release
+// controllers and the artifact hosts. It adds per-attempt timeouts, retries
+// with jittered exponential backoff and status code checking to plain GETs.
+// Concurrent requests for the same URL are coalesced into a single download.
+type Client struct {
+	httpClient *http.Client
+	options    Options
+	inflight   group
+}
+
+// New creates a Client with the given options
+func New(options Options) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = options.ResponseHeaderTimeout
+	return &Client{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   options.Timeout,
+		},
+		options: options,
+	}
+}
+
+var defaultClient atomic.Pointer[Client]
+
+func init() {
+	defaultClient.Store(New(DefaultOptions()))
+}
+
+// Default returns the client used by the server
+func Default() *Client {
+	return defaultClient.Load()
+}
+
+// SetDefault replaces the client used by the server
+func SetDefault(c *Client) {
+	defaultClient.Store(c)
+}
+
+// Cache returns the response cache of the client, nil when caching is disabled
+func (c *Client) Cache() *cache.Cache {
+	return c.options.Cache
+}
+
+// GetMutable is Get for resources which change over time, such as the release
+// controller API. Responses are served from the cache for up to CacheTTL.
+func (c *Client) GetMutable(ctx context.Context, url string) ([]byte, error) {
+	return c.getCached(ctx, url, c.options.CacheTTL)
+}
+
+// GetImmutable is Get for resources which never change once they exist, such as
+// the artifacts of a finished job. Responses are cached until evicted.
+func (c *Client) GetImmutable(ctx context.Context, url string) ([]byte, error) {
+	return c.getCached(ctx, url, cache.Forever)
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context whose requests are not served from the cache, for
+// callers which must see the current state such as pollers. Responses are still
+// cached for the other callers.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func (c *Client) getCached(ctx context.Context, url string, ttl time.Duration) ([]byte, error) {
+	if c.options.Cache == nil || ttl == 0 {
+		return c.Get(ctx, url)
+	}
+	if bypass, _ := ctx.Value(bypassCacheKey{}).(bool); !bypass {
+		if data, ok := c.options.Cache.Get(url); ok {
+			return data, nil
+		}
+	}
+	return c.inflight.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		data, err := c.fetch(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		// a failure to cache the response must not fail the request
+		_ = c.options.Cache.Put(url, data, ttl)
+		return data, nil
+	})
+}
+
+// Get fetches the given URL and returns the response body. Network errors, 429 and
+// 5xx responses are retried until the retries are exhausted or ctx is done. The
+// returned data may be shared with concurrent callers and must not be modified.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	return c.inflight.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		return c.fetch(ctx, url)
+	})
+}
+
+// fetch performs the attempts of a single Get
+func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		data, retryAfter, err := c.get(ctx, url)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt >= c.options.Retries || !retryable(err) {
+			return nil, lastErr
+		}
+		delay := c.backoff(attempt)
+		if retryAfter > delay {
+			delay = min(retryAfter, c.options.MaxBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// get performs a single attempt, returning the delay requested by the server if any
+func (c *Client) get(ctx context.Context, url string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request for %s: %w", url, err)
+	}
+	req.Header.Set("User-Agent", c.options.UserAgent)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// drain a little of the body so the connection can be reused
+		_, _ = io.CopyN(io.Discard, resp.Body, 4096)
+		return nil, retryAfter(resp), &StatusError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading response body of %s: %w", url, err)
+	}
+	return data, 0, nil
+}
+
+// backoff returns the delay before the given retry, with full jitter
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.options.MinBackoff << attempt
+	if delay <= 0 || delay > c.options.MaxBackoff {
+		delay = c.options.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// connection resets and truncated bodies surface as plain errors
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
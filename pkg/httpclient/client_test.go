@@ -0,0 +1,152 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/cache"
+)
+
+func testClient() *Client {
+	options := DefaultOptions()
+	options.MinBackoff = time.Millisecond
+	options.MaxBackoff = 5 * time.Millisecond
+	return New(options)
+}
+
+func TestGetRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() != DefaultOptions().UserAgent {
+			t.Errorf("unexpected user agent %q", r.UserAgent())
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	data, err := testClient().Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "ok" || calls.Load() != 3 {
+		t.Errorf("unexpected result %q after %d calls", data, calls.Load())
+	}
+}
+
+func TestGetDoesNotRetryNotFound(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	_, err := testClient().Get(context.Background(), srv.URL)
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single call, got %d", calls.Load())
+	}
+}
+
+func TestGetHonorsCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := testClient().Get(ctx, srv.URL); err != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestGetCoalescesConcurrentRequests(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := testClient()
+	// a caller giving up must not cancel the download for the others
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		_, err := c.Get(ctx, srv.URL)
+		cancelled <- err
+	}()
+	results := make(chan string, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			data, err := c.Get(context.Background(), srv.URL)
+			if err != nil {
+				t.Error(err)
+			}
+			results <- string(data)
+		}()
+	}
+	// wait for every caller to join the download
+	for waiters := 0; waiters < 6; time.Sleep(time.Millisecond) {
+		c.inflight.mu.Lock()
+		if call, ok := c.inflight.calls[srv.URL]; ok {
+			waiters = call.waiters
+		}
+		c.inflight.mu.Unlock()
+	}
+	cancel()
+	if err := <-cancelled; err != context.Canceled {
+		t.Errorf("expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+	close(release)
+	for i := 0; i < 5; i++ {
+		if data := <-results; data != "ok" {
+			t.Errorf("unexpected body %q", data)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single request, got %d", calls.Load())
+	}
+}
+
+func TestGetMutableBypassCache(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strconv.Itoa(int(calls.Add(1)))))
+	}))
+	defer srv.Close()
+	c, err := cache.New(t.TempDir(), 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := DefaultOptions()
+	options.Cache = c
+	options.CacheTTL = time.Hour
+	client := New(options)
+
+	for _, want := range []string{"1", "1"} {
+		if data, err := client.GetMutable(context.Background(), srv.URL); err != nil || string(data) != want {
+			t.Errorf("expected the cached response %s, got %s %v", want, data, err)
+		}
+	}
+	if data, err := client.GetMutable(BypassCache(context.Background()), srv.URL); err != nil || string(data) != "2" {
+		t.Errorf("expected a fresh response, got %s %v", data, err)
+	}
+	if data, err := client.GetMutable(context.Background(), srv.URL); err != nil || string(data) != "2" {
+		t.Errorf("expected the fresh response to be cached, got %s %v", data, err)
+	}
+}
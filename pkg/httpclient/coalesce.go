@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+)
+
+// call is a fetch shared by every concurrent caller asking for the same URL
+type call struct {
+	done chan struct{}
+	data []byte
+	err  error
+	// waiters is the number of callers still interested in the result, the fetch
+	// is cancelled once all of them have given up
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// group coalesces concurrent fetches of the same URL into a single one
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do runs fn once for all the concurrent callers with the same key and hands
+// each of them the result. fn runs with a context which keeps the values of the
+// first caller's ctx and is only cancelled when every caller's ctx is done. The
+// returned data is shared between the callers and must not be modified.
+func (g *group) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call{}
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		fnCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = c
+		go func() {
+			c.data, c.err = fn(fnCtx)
+			cancel()
+			g.mu.Lock()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			close(c.done)
+		}()
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.data, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			// let a new caller start a fresh fetch instead of joining the cancelled one
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// authorize wraps a tool handler so that it is only called for the clients allowed
+// to use the tool. Requests without a principal, such as stdio ones, are not restricted.
+func authorize(tool string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := allowed(ctx, "call the", tool, "tool"); err != nil {
+			return NewTextResult("", err), nil
+		}
+		return handler(ctx, ctr)
+	}
+}
+
+// authorizeResource wraps a resource handler so that a resource is only read by the
+// clients allowed to call the tool serving the same data
+func authorizeResource(handler server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if err := allowed(ctx, "read", request.Params.URI, "resource"); err != nil {
+			return nil, err
+		}
+		return handler(ctx, request)
+	}
+}
+
+// authorizePrompt wraps a prompt handler so that it is only called for the clients
+// whose allowlist names the prompt
+func authorizePrompt(name string, handler server.PromptHandlerFunc) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		if err := allowed(ctx, "get the", name, "prompt"); err != nil {
+			return nil, err
+		}
+		return handler(ctx, request)
+	}
+}
+
+// allowed checks that the principal of a request may use a tool, a prompt or a resource,
+// resources being allowed with the tool serving the same data
+func allowed(ctx context.Context, verb, name, kind string) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	entry := name
+	if kind == "resource" {
+		entry = resourceTool(name)
+	}
+	if !principal.Allows(entry) {
+		return fmt.Errorf("%s is not allowed to %s %s %s", principal.Name, verb, name, kind)
+	}
+	return nil
+}
+
+// sessionOwner returns the credential a session opened by a request belongs to, empty
+// for unauthenticated requests. Later requests of the session must use it.
+func sessionOwner(ctx context.Context) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return principal.ID
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAuthorize(t *testing.T) {
+	handler := authorize("get_cache_stats", func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return NewTextResult("stats", nil), nil
+	})
+	for _, tc := range []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"stdio", context.Background(), true},
+		{"allowed", auth.NewContext(context.Background(), &auth.Principal{Name: "team", Tools: []string{"get_cache_stats"}}), true},
+		{"denied", auth.NewContext(context.Background(), &auth.Principal{Name: "bot", Tools: []string{"latest_accepted_release"}}), false},
+	} {
+		result, err := handler(tc.ctx, mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.IsError == tc.allowed {
+			t.Errorf("%s: expected allowed=%t, got %s", tc.name, tc.allowed, resultText(t, result))
+		}
+	}
+}
+
+func TestAuthorizeResourcesAndPrompts(t *testing.T) {
+	bot := auth.NewContext(context.Background(), &auth.Principal{Name: "bot", Tools: []string{"list_recent_tags"}})
+	read := authorizeResource(func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	})
+	for uri, allowed := range map[string]bool{
+		"releasecontroller://ocp/4.19.0-0.nightly":                                    true,
+		"releasecontroller://ocp":                                                     false,
+		"releasecontroller://ocp/4.19.0-0.nightly/4.19.0-0.nightly-2025-05-02-000000": false,
+	} {
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = uri
+		if _, err := read(bot, request); (err == nil) != allowed {
+			t.Errorf("%s: expected allowed=%t, got %v", uri, allowed, err)
+		}
+	}
+
+	prompt := authorizePrompt("triage_rejected_payload", func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	if _, err := prompt(bot, mcp.GetPromptRequest{}); err == nil {
+		t.Errorf("expected a prompt outside the allowlist to be denied")
+	}
+	if _, err := prompt(context.Background(), mcp.GetPromptRequest{}); err != nil {
+		t.Errorf("expected stdio to get every prompt: %v", err)
+	}
+
+	sub := newSubscriptions(&changingReleaseController{}, time.Hour, time.Hour)
+	response, _ := sub.intercept(bot, "session", func(mcp.JSONRPCNotification) bool { return true }, subscribeMessage(methodResourcesSubscribe, "releasecontroller://ocp/4.19.0-0.nightly/4.19.0-0.nightly-2025-05-02-000000"))
+	if _, ok := response.(mcp.JSONRPCError); !ok {
+		t.Errorf("expected a subscription outside the allowlist to be denied, got %+v", response)
+	}
+}
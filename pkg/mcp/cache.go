@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/httpclient"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Register the tools reporting on the response cache.
+func (s *Server) initCache() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("get_cache_stats",
+			mcp.WithDescription("Gets the hit, miss and eviction counts and the size of the response cache of the server."),
+		), Handler: s.getCacheStats},
+	}
+}
+
+func (s *Server) getCacheStats(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	c := httpclient.Default().Cache()
+	if c == nil {
+		return NewTextResult("The response cache is disabled.", nil), nil
+	}
+	stats := c.Stats()
+	hitRate := 0.0
+	if total := stats.Hits + stats.Misses; total > 0 {
+		hitRate = 100 * float64(stats.Hits) / float64(total)
+	}
+	return NewTextResult(fmt.Sprintf("Directory: %s\nEntries: %d\nSize: %d/%d bytes\nHits: %d\nMisses: %d\nHit rate: %.1f%%\nEvictions: %d\n",
+		stats.Dir, stats.Entries, stats.Size, stats.MaxSize, stats.Hits, stats.Misses, hitRate, stats.Evictions), nil), nil
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/export"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WithExportDir sets the directory export_release_data writes to, exports are disabled if empty
+func WithExportDir(dir string) Option {
+	return func(s *Server) {
+		s.exportDir = dir
+	}
+}
+
+// Register the tools snapshotting release controller data to the export directory.
+func (s *Server) initExport() []server.ServerTool {
+	return []server.ServerTool{
+		{Tool: mcp.NewTool("export_release_data",
+			mcp.WithDescription("Saves the tags of a release stream, or the full release information of a tag, as JSON files in the export directory of the server, for archiving and offline analysis. Returns the path of the file written."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithString("tag", mcp.Description("The release tag to export, the tags of the stream are exported if empty")),
+			mcp.WithString("directory", mcp.Description("Sub-directory of the export directory to write to")),
+		), Handler: s.exportReleaseData},
+	}
+}
+
+func (s *Server) exportReleaseData(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.exportDir == "" {
+		return NewTextResult("", fmt.Errorf("exports are disabled, the server must be started with --export-dir")), nil
+	}
+	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+	stream := ctr.Params.Arguments["stream"].(string)
+	tag, _ := ctr.Params.Arguments["tag"].(string)
+	dir := s.exportDir
+	if sub, _ := ctr.Params.Arguments["directory"].(string); sub != "" {
+		if !filepath.IsLocal(sub) {
+			return NewTextResult("", fmt.Errorf("directory %s must be relative to the export directory", sub)), nil
+		}
+		dir = filepath.Join(dir, sub)
+	}
+	path, err := export.Export(ctx, s.releaseController, releasecontroller, stream, tag, dir)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	return NewTextResult(fmt.Sprintf("Exported to %s", path), nil), nil
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Output formats of the tool results
+const (
+	// formatText is free text meant to be read by a model, the default
+	formatText = "text"
+	// formatJSON is the result marshalled as JSON, see the api package for the schemas
+	formatJSON = "json"
+	// formatMarkdown is the result rendered as markdown tables and lists
+	formatMarkdown = "markdown"
+)
+
+// withFormat adds the optional format argument to a tool
+func withFormat() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("Output format of the result: text (default), json or markdown"),
+		mcp.Enum(formatText, formatJSON, formatMarkdown),
+	)
+}
+
+// outputFormat returns the format requested by the caller of a tool
+func outputFormat(ctr mcp.CallToolRequest) (string, error) {
+	format, _ := ctr.Params.Arguments["format"].(string)
+	switch format {
+	case "", formatText:
+		return formatText, nil
+	case formatJSON, formatMarkdown:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, expected one of text, json or markdown", format)
+	}
+}
+
+// NewRenderedResult renders the result of a call in the format requested by the
+// caller, unless the call failed
+func NewRenderedResult[T any](ctr mcp.CallToolRequest, result T, err error, text, markdown func(T) string) *mcp.CallToolResult {
+	if err != nil {
+		return NewTextResult("", err)
+	}
+	format, err := outputFormat(ctr)
+	if err != nil {
+		return NewTextResult("", err)
+	}
+	switch format {
+	case formatJSON:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return NewTextResult("", fmt.Errorf("error marshaling result: %w", err))
+		}
+		return NewTextResult(string(data), nil)
+	case formatMarkdown:
+		return NewTextResult(markdown(result), nil)
+	default:
+		return NewTextResult(text(result), nil)
+	}
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func callWithFormat(format string) mcp.CallToolRequest {
+	var ctr mcp.CallToolRequest
+	ctr.Params.Arguments = map[string]interface{}{}
+	if format != "" {
+		ctr.Params.Arguments["format"] = format
+	}
+	return ctr
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) != 1 {
+		t.Fatalf("expected one content item, got %d", len(result.Content))
+	}
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestNewRenderedResultFormats(t *testing.T) {
+	jobs := []api.VerificationJob{
+		{Name: "periodic-e2e-aws", Kind: api.JobKindBlocking, State: "Failed", URL: "https://prow.example.com/view/1"},
+	}
+
+	text := resultText(t, NewRenderedResult(callWithFormat(""), jobs, nil, renderFailedJobs, markdownFailedJobs))
+	if text != "periodic-e2e-aws: https://prow.example.com/view/1" {
+		t.Errorf("unexpected text output: %q", text)
+	}
+
+	var decoded []api.VerificationJob
+	if err := json.Unmarshal([]byte(resultText(t, NewRenderedResult(callWithFormat("json"), jobs, nil, renderFailedJobs, markdownFailedJobs))), &decoded); err != nil {
+		t.Fatalf("json output does not decode: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0] != jobs[0] {
+		t.Errorf("unexpected json output: %+v", decoded)
+	}
+
+	markdown := resultText(t, NewRenderedResult(callWithFormat("markdown"), jobs, nil, renderFailedJobs, markdownFailedJobs))
+	if !strings.Contains(markdown, "| [periodic-e2e-aws](https://prow.example.com/view/1) | blocking | Failed |") {
+		t.Errorf("unexpected markdown output: %q", markdown)
+	}
+
+	if result := NewRenderedResult(callWithFormat("yaml"), jobs, nil, renderFailedJobs, markdownFailedJobs); !result.IsError {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
+
+func TestMarkdownTableEscapesCells(t *testing.T) {
+	table := markdownTable([]string{"Key", "Value"}, [][]string{{"a|b", "line1\nline2"}})
+	if !strings.Contains(table, "| a\\|b | line1<br>line2 |") {
+		t.Errorf("cells not escaped: %q", table)
+	}
+}
+
+func TestRenderFlakyTestsKeepsLogLines(t *testing.T) {
+	results := &api.TestResults{
+		Tests: []string{"[sig-network] flaky"},
+		Lines: []string{`flaked: (1.2s) 2025-01-01T00:00:00 "[sig-network] flaky"`, "Flaky tests:", "", "[sig-network] flaky"},
+	}
+	if text := renderFlakyTests(results); text != strings.Join(results.Lines, "\n")+"\n" {
+		t.Errorf("unexpected text output: %q", text)
+	}
+	if text := renderFlakyTests(&api.TestResults{}); text != "No flaky tests found." {
+		t.Errorf("unexpected text output without flaky tests: %q", text)
+	}
+}
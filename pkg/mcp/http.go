@@ -0,0 +1,513 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// sessionIDHeader carries the session of a streamable HTTP client
+	sessionIDHeader = "Mcp-Session-Id"
+	// maxMessageSize is the maximum size of a POSTed JSON-RPC message or batch
+	maxMessageSize = 4 * 1024 * 1024
+	// maxReplayEvents is the number of events kept per session to resume a stream
+	maxReplayEvents = 256
+	// keepAliveInterval is how often a comment is written to idle event streams
+	keepAliveInterval = 30 * time.Second
+	// DefaultSessionIdleTimeout is how long a streamable HTTP session is kept without any request
+	DefaultSessionIdleTimeout = 30 * time.Minute
+)
+
+// StreamableHTTPServer serves the MCP streamable HTTP transport. Clients POST JSON-RPC
+// messages to a single endpoint and get the responses back as JSON. They can open a
+// GET event stream on the same endpoint to receive the server notifications, and
+// resume it with Last-Event-ID after a disconnection.
+type StreamableHTTPServer struct {
+	server      *server.MCPServer
+	endpoint    string
+	idleTimeout time.Duration
+	done        chan struct{}
+	// interceptor handles the messages the MCP server does not, if set
+	interceptor messageInterceptor
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+// StreamableHTTPOption configures a StreamableHTTPServer
+type StreamableHTTPOption func(*StreamableHTTPServer)
+
+// WithEndpoint sets the path of the MCP endpoint, /mcp by default
+func WithEndpoint(endpoint string) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.endpoint = "/" + strings.Trim(endpoint, "/")
+	}
+}
+
+// WithSessionIdleTimeout sets how long a session is kept without any request
+func WithSessionIdleTimeout(timeout time.Duration) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.idleTimeout = timeout
+	}
+}
+
+// withInterceptor passes the messages to an interceptor before the MCP server
+func withInterceptor(interceptor messageInterceptor) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.interceptor = interceptor
+	}
+}
+
+// NewStreamableHTTPServer returns a streamable HTTP transport for the MCP server, served as
+// the handler of an HTTP server. Shutdown must be called to close the sessions.
+func NewStreamableHTTPServer(mcpServer *server.MCPServer, opts ...StreamableHTTPOption) *StreamableHTTPServer {
+	s := &StreamableHTTPServer{
+		server:      mcpServer,
+		endpoint:    "/mcp",
+		idleTimeout: DefaultSessionIdleTimeout,
+		done:        make(chan struct{}),
+		sessions:    map[string]*httpSession{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.reapIdleSessions()
+	return s
+}
+
+// Shutdown closes the event streams and every session. The HTTP server serving the
+// transport waits for the pending requests itself.
+func (s *StreamableHTTPServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	for id, session := range s.sessions {
+		s.closeSession(id, session)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// ServeHTTP dispatches the requests to the MCP endpoint by method
+func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.endpoint {
+		http.NotFound(w, r)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "Forbidden origin", http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		s.handlePost(w, r)
+	case http.MethodGet:
+		s.handleGet(w, r)
+	case http.MethodDelete:
+		s.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// sameOrigin rejects browser requests coming from other sites, to protect local
+// servers against DNS rebinding
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// handlePost processes a JSON-RPC message or batch and returns the responses as JSON
+func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		writeJSONRPCError(w, http.StatusRequestEntityTooLarge, mcp.INVALID_REQUEST, "Message too large")
+		return
+	}
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+	var messages []json.RawMessage
+	if batch {
+		err = json.Unmarshal(body, &messages)
+	} else {
+		messages = []json.RawMessage{body}
+		err = json.Unmarshal(body, new(json.RawMessage))
+	}
+	if err != nil || len(messages) == 0 {
+		writeJSONRPCError(w, http.StatusBadRequest, mcp.PARSE_ERROR, "Parse error")
+		return
+	}
+
+	var session *httpSession
+	if isInitialize(messages) {
+		if r.Header.Get(sessionIDHeader) != "" {
+			writeJSONRPCError(w, http.StatusBadRequest, mcp.INVALID_REQUEST, "Initialize must not carry a session")
+			return
+		}
+		session, err = s.newSession(sessionOwner(r.Context()))
+		if err != nil {
+			writeJSONRPCError(w, http.StatusServiceUnavailable, mcp.INTERNAL_ERROR, err.Error())
+			return
+		}
+	} else {
+		var status int
+		session, status = s.session(r)
+		if session == nil {
+			writeJSONRPCError(w, status, mcp.INVALID_REQUEST, http.StatusText(status))
+			return
+		}
+	}
+	defer s.touch(session)
+
+	ctx := s.server.WithContext(r.Context(), session)
+	var responses []mcp.JSONRPCMessage
+	for _, message := range messages {
+		if s.interceptor != nil {
+			if response, ok := s.interceptor.intercept(ctx, session.id, session.notify, message); ok {
+				responses = append(responses, response)
+				continue
+			}
+		}
+		if response := s.server.HandleMessage(ctx, message); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	w.Header().Set(sessionIDHeader, session.id)
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		_ = json.NewEncoder(w).Encode(responses)
+	} else {
+		_ = json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// isInitialize tells whether the messages open a session
+func isInitialize(messages []json.RawMessage) bool {
+	for _, message := range messages {
+		var m struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(message, &m) == nil && m.Method == string(mcp.MethodInitialize) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleGet streams the notifications of a session as server-sent events, starting
+// after the Last-Event-ID of a resumed stream
+func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "Event streams must accept text/event-stream", http.StatusMethodNotAllowed)
+		return
+	}
+	session, status := s.session(r)
+	if session == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	var last int64
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		var err error
+		if last, err = strconv.ParseInt(id, 10, 64); err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+	s.streamOpened(session)
+	defer s.streamClosed(session)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set(sessionIDHeader, session.id)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		events, wake := session.eventsAfter(last)
+		for _, event := range events {
+			fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", event.id, event.data)
+			last = event.id
+		}
+		flusher.Flush()
+		select {
+		case <-wake:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-session.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleDelete terminates a session
+func (s *StreamableHTTPServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	session, status := s.session(r)
+	if session == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	s.mu.Lock()
+	s.closeSession(session.id, session)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// session returns the session of the request, or the HTTP status to fail the request with.
+// A session can only be used by the principal which opened it.
+func (s *StreamableHTTPServer) session(r *http.Request) (*httpSession, int) {
+	id := r.Header.Get(sessionIDHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	if session.owner != sessionOwner(r.Context()) {
+		return nil, http.StatusForbidden
+	}
+	session.lastUsed = time.Now()
+	return session, http.StatusOK
+}
+
+// newSession opens a session of a principal and registers it with the MCP server to get notifications
+func (s *StreamableHTTPServer) newSession(owner string) (*httpSession, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("error generating session ID: %w", err)
+	}
+	session := &httpSession{
+		id:            hex.EncodeToString(id),
+		owner:         owner,
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+		wake:          make(chan struct{}),
+		done:          make(chan struct{}),
+		lastUsed:      time.Now(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return nil, errors.New("server is shutting down")
+	default:
+	}
+	if err := s.server.RegisterSession(session); err != nil {
+		return nil, err
+	}
+	s.sessions[session.id] = session
+	go session.pump()
+	return session, nil
+}
+
+// closeSession forgets a session and ends its event streams. It must be called with mu held.
+func (s *StreamableHTTPServer) closeSession(id string, session *httpSession) {
+	if s.sessions[id] != session {
+		return
+	}
+	delete(s.sessions, id)
+	s.server.UnregisterSession(id)
+	session.mu.Lock()
+	session.closed = true
+	session.mu.Unlock()
+	close(session.done)
+	if s.interceptor != nil {
+		s.interceptor.sessionClosed(id)
+	}
+}
+
+// touch records the end of a request of the session
+func (s *StreamableHTTPServer) touch(session *httpSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session.lastUsed = time.Now()
+}
+
+func (s *StreamableHTTPServer) streamOpened(session *httpSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session.streams++
+}
+
+func (s *StreamableHTTPServer) streamClosed(session *httpSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session.streams--
+	session.lastUsed = time.Now()
+}
+
+// reapIdleSessions closes the sessions without any request or open stream for longer
+// than the idle timeout, until the server shuts down
+func (s *StreamableHTTPServer) reapIdleSessions() {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(min(s.idleTimeout/2, time.Minute))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for id, session := range s.sessions {
+				if session.streams == 0 && now.Sub(session.lastUsed) > s.idleTimeout {
+					s.closeSession(id, session)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// httpEvent is a notification sent on the event streams of a session
+type httpEvent struct {
+	id   int64
+	data []byte
+}
+
+// httpSession is a client session of the streamable HTTP transport
+type httpSession struct {
+	id string
+	// owner is the principal which opened the session, empty if not authenticated
+	owner         string
+	initialized   atomic.Bool
+	notifications chan mcp.JSONRPCNotification
+	done          chan struct{}
+
+	// lastUsed and streams are guarded by the mutex of the server
+	lastUsed time.Time
+	streams  int
+
+	mu sync.Mutex
+	// closed is set when the session is closed, nothing is sent to it afterwards
+	closed bool
+	// events holds the most recent events, to resume a stream
+	events []httpEvent
+	nextID int64
+	// wake is closed and replaced whenever an event is added
+	wake chan struct{}
+}
+
+func (s *httpSession) SessionID() string {
+	return s.id
+}
+
+func (s *httpSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *httpSession) Initialize() {
+	s.initialized.Store(true)
+}
+
+func (s *httpSession) Initialized() bool {
+	return s.initialized.Load()
+}
+
+// notify queues a notification for the event streams of the session, unless it is closed
+func (s *httpSession) notify(notification mcp.JSONRPCNotification) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	select {
+	case s.notifications <- notification:
+	default:
+	}
+	return true
+}
+
+// pump turns the notifications sent to the session into events, until the session is closed
+func (s *httpSession) pump() {
+	for {
+		select {
+		case notification := <-s.notifications:
+			data, err := json.Marshal(notification)
+			if err != nil {
+				continue
+			}
+			s.mu.Lock()
+			s.nextID++
+			s.events = append(s.events, httpEvent{id: s.nextID, data: data})
+			if len(s.events) > maxReplayEvents {
+				s.events = s.events[len(s.events)-maxReplayEvents:]
+			}
+			close(s.wake)
+			s.wake = make(chan struct{})
+			s.mu.Unlock()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// eventsAfter returns the kept events following the given event ID, and a channel
+// closed when the next event is added
+func (s *httpSession) eventsAfter(last int64) ([]httpEvent, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []httpEvent
+	for _, event := range s.events {
+		if event.id > last {
+			events = append(events, event)
+		}
+	}
+	return events, s.wake
+}
+
+// writeJSONRPCError fails a request with a JSON-RPC error without ID
+func writeJSONRPCError(w http.ResponseWriter, status int, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(mcp.JSONRPCError{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Error: struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Data    any    `json:"data,omitempty"`
+		}{Code: code, Message: message},
+	})
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func post(t *testing.T, url, session, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if session != "" {
+		req.Header.Set(sessionIDHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	return resp
+}
+
+// readEvent reads the next event of a stream, returning its ID and data
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var id, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error reading event stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && data != "":
+			return id, data
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openStream(t *testing.T, url, session, lastEventID string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionIDHeader, session)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the stream to open, got %s", resp.Status)
+	}
+	return resp
+}
+
+func TestStreamableHTTPSessions(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	httpServer := NewStreamableHTTPServer(mcpServer)
+	ts := httptest.NewServer(httpServer)
+	defer ts.Close()
+	defer func() { _ = httpServer.Shutdown(t.Context()) }()
+	url := ts.URL + "/mcp"
+
+	resp := post(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a request without session to fail, got %s", resp.Status)
+	}
+
+	resp = post(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	session := resp.Header.Get(sessionIDHeader)
+	if resp.StatusCode != http.StatusOK || session == "" {
+		t.Fatalf("initialize failed: %s, session %q", resp.Status, session)
+	}
+
+	resp = post(t, url, session, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected a notification to be accepted, got %s", resp.Status)
+	}
+
+	resp = post(t, url, session, `[{"jsonrpc":"2.0","id":2,"method":"ping"},{"jsonrpc":"2.0","id":3,"method":"tools/list"}]`)
+	var batch []map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil || len(batch) != 2 {
+		t.Fatalf("expected two responses to the batch, got %v (%v)", batch, err)
+	}
+
+	// Notifications are sent on the event stream, and replayed when it is resumed
+	stream := openStream(t, url, session, "")
+	mcpServer.AddTool(mcp.NewTool("first"), nil)
+	firstID, data := readEvent(t, bufio.NewReader(stream.Body))
+	if !strings.Contains(data, "notifications/tools/list_changed") {
+		t.Errorf("unexpected event: %s", data)
+	}
+	stream.Body.Close()
+	mcpServer.AddTool(mcp.NewTool("second"), nil)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if events, _ := httpServer.sessions[session].eventsAfter(0); len(events) == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stream = openStream(t, url, session, firstID)
+	defer stream.Body.Close()
+	if secondID, _ := readEvent(t, bufio.NewReader(stream.Body)); secondID == firstID || secondID == "" {
+		t.Errorf("expected the stream to resume after event %s, got event %s", firstID, secondID)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, url, nil)
+	req.Header.Set(sessionIDHeader, session)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE failed: %v %v", resp, err)
+	}
+	resp = post(t, url, session, `{"jsonrpc":"2.0","id":4,"method":"ping"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a closed session to be unknown, got %s", resp.Status)
+	}
+}
+
+func TestStreamableHTTPSessionOwner(t *testing.T) {
+	httpServer := NewStreamableHTTPServer(server.NewMCPServer("test", "0.0.0"))
+	// The principal is the bearer token, as set by the authentication middleware
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, id, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		_, name, _ := strings.Cut(id, ":")
+		httpServer.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), &auth.Principal{Name: name, ID: id})))
+	}))
+	defer ts.Close()
+	defer func() { _ = httpServer.Shutdown(t.Context()) }()
+	postAs := func(principal, session, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+principal)
+		if session != "" {
+			req.Header.Set(sessionIDHeader, session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := postAs("token:alice", "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	session := resp.Header.Get(sessionIDHeader)
+	if resp.StatusCode != http.StatusOK || session == "" {
+		t.Fatalf("initialize failed: %s", resp.Status)
+	}
+	if resp := postAs("token:alice", session, `{"jsonrpc":"2.0","id":2,"method":"ping"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the owner of the session to use it, got %s", resp.Status)
+	}
+	for _, principal := range []string{"token:mallory", "cert:alice"} {
+		if resp := postAs(principal, session, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("expected %s to be rejected, got %s", principal, resp.Status)
+		}
+	}
+}
+
+func TestSSESessionOwner(t *testing.T) {
+	sse := &SSEServer{SSEServer: server.NewSSEServer(server.NewMCPServer("test", "0.0.0")), interceptor: newSubscriptions(&changingReleaseController{}, time.Hour, time.Hour)}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, name, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		sse.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), &auth.Principal{Name: name, ID: "token:" + name})))
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/sse", nil)
+	req.Header.Set("Authorization", "Bearer alice")
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+	// The endpoint event carries the session ID, its lines end with \r\n
+	var endpoint string
+	for r := bufio.NewReader(stream.Body); endpoint == ""; {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error reading event stream: %v", err)
+		}
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			endpoint = data
+		}
+	}
+	for principal, status := range map[string]int{"mallory": http.StatusForbidden, "alice": http.StatusAccepted} {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+endpoint, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		req.Header.Set("Authorization", "Bearer "+principal)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected %d, got %s", principal, status, resp.Status)
+		}
+	}
+}
+
+func TestStreamableHTTPNotifyClosedSession(t *testing.T) {
+	httpServer := NewStreamableHTTPServer(server.NewMCPServer("test", "0.0.0"))
+	defer func() { _ = httpServer.Shutdown(t.Context()) }()
+	session, err := httpServer.newSession("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notification := mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION}
+	if !session.notify(notification) {
+		t.Errorf("expected an open session to accept notifications")
+	}
+	httpServer.mu.Lock()
+	httpServer.closeSession(session.id, session)
+	httpServer.mu.Unlock()
+	for range 100 {
+		if session.notify(notification) {
+			t.Fatalf("expected a closed session to refuse notifications")
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+)
+
+// Markdown renderers for the results returned by the ReleaseController and Cluster interfaces
+
+// markdownCell escapes a value so that it fits in a single table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
+
+// markdownTable renders the rows as a table with the given header
+func markdownTable(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString(strings.Repeat("| --- ", len(header)) + "|\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = markdownCell(cell)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return b.String()
+}
+
+// markdownLink renders a link, or just the text if there is no URL
+func markdownLink(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// markdownCode renders the text as a fenced code block
+func markdownCode(text, lang string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%s%s\n%s\n%s\n", fence, lang, strings.TrimSuffix(text, "\n"), fence)
+}
+
+// markdownList renders the items as a bullet list
+func markdownList(items []string, empty string) string {
+	if len(items) == 0 {
+		return empty
+	}
+	var b strings.Builder
+	for _, item := range items {
+		fmt.Fprintf(&b, "- %s\n", item)
+	}
+	return b.String()
+}
+
+func markdownLog(log string) string {
+	return markdownCode(log, "")
+}
+
+func markdownPods(pods []api.PodSummary, empty string) string {
+	if len(pods) == 0 {
+		return empty
+	}
+	var rows [][]string
+	for _, pod := range pods {
+		rows = append(rows, []string{pod.Namespace, pod.Name, pod.Node, pod.Phase, pod.Container, pod.Reason, pod.Message})
+	}
+	return markdownTable([]string{"Namespace", "Pod", "Node", "Phase", "Container", "Reason", "Message"}, rows)
+}
+
+func markdownPodContainers(containers *api.PodContainers) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Containers in pod %s/%s\n\n", containers.Namespace, containers.Pod)
+	for _, group := range []struct {
+		title string
+		names []string
+	}{
+		{"Init containers", containers.InitContainers},
+		{"Containers", containers.Containers},
+		{"Ephemeral containers", containers.EphemeralContainers},
+	} {
+		if len(group.names) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "**%s**\n\n%s\n", group.title, markdownList(group.names, ""))
+	}
+	return b.String()
+}
+
+func markdownOperatorStatuses(operators []api.OperatorStatus) string {
+	var rows [][]string
+	for _, op := range operators {
+		rows = append(rows, []string{op.Name, renderConditionStatus(op.Available), renderConditionStatus(op.Progressing), renderConditionStatus(op.Degraded)})
+	}
+	return markdownTable([]string{"Operator", "Available", "Progressing", "Degraded"}, rows)
+}
+
+func markdownClusterVersion(cv *api.ClusterVersionSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Cluster version %s\n\n", cv.Version)
+	fmt.Fprintf(&b, "- **Desired image:** `%s`\n", cv.Image)
+	fmt.Fprintf(&b, "- **Desired URL:** %s\n", cv.URL)
+	fmt.Fprintf(&b, "- **Available updates:** %d\n", len(cv.AvailableUpdates))
+	for _, update := range cv.AvailableUpdates {
+		fmt.Fprintf(&b, "  - %s\n", update)
+	}
+	if len(cv.History) > 0 {
+		b.WriteString("\n#### Update history\n\n")
+		var rows [][]string
+		for _, hist := range cv.History {
+			completed := "N/A"
+			if hist.Completed != nil {
+				completed = hist.Completed.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{hist.Version, hist.State, fmt.Sprintf("%t", hist.Verified), hist.Started.Format(time.RFC3339), completed, hist.AcceptedRisks})
+		}
+		b.WriteString(markdownTable([]string{"Version", "State", "Verified", "Started", "Completed", "Accepted risks"}, rows))
+	}
+	return b.String()
+}
+
+func markdownNodeInfo(info *api.NodeInfo) string {
+	rows := [][]string{
+		{"Architecture", orNotAvailable(info.Architecture)},
+		{"Boot ID", orNotAvailable(info.BootID)},
+		{"Container Runtime Version", orNotAvailable(info.ContainerRuntimeVersion)},
+		{"Kernel Version", orNotAvailable(info.KernelVersion)},
+		{"KubeProxy Version", orNotAvailable(info.KubeProxyVersion)},
+		{"Kubelet Version", orNotAvailable(info.KubeletVersion)},
+		{"Machine ID", orNotAvailable(info.MachineID)},
+		{"Operating System", orNotAvailable(info.OperatingSystem)},
+		{"OS Image", orNotAvailable(info.OSImage)},
+		{"System UUID", orNotAvailable(info.SystemUUID)},
+	}
+	return fmt.Sprintf("### Node %s\n\n%s", info.Name, markdownTable([]string{"Field", "Value"}, rows))
+}
+
+func markdownNodesInfo(infos []api.NodeInfo) string {
+	var rows [][]string
+	for _, info := range infos {
+		rows = append(rows, []string{info.Name, info.Architecture, info.OSImage, info.KernelVersion, info.KubeletVersion, info.ContainerRuntimeVersion})
+	}
+	return markdownTable([]string{"Node", "Architecture", "OS Image", "Kernel Version", "Kubelet Version", "Container Runtime Version"}, rows)
+}
+
+// markdownMap renders the entries of a map sorted by key, under a heading
+func markdownMap(kind, node string, m map[string]string) string {
+	if len(m) == 0 {
+		return fmt.Sprintf("No %s found for node %s\n", strings.ToLower(kind), node)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var rows [][]string
+	for _, k := range keys {
+		rows = append(rows, []string{"`" + k + "`", m[k]})
+	}
+	return fmt.Sprintf("### %s for node %s\n\n%s", kind, node, markdownTable([]string{"Key", "Value"}, rows))
+}
+
+func markdownNodeLabels(labels *api.NodeLabels) string {
+	return markdownMap("Labels", labels.Name, labels.Labels)
+}
+
+func markdownNodesLabels(labels []api.NodeLabels) string {
+	var sections []string
+	for _, l := range labels {
+		sections = append(sections, markdownNodeLabels(&l))
+	}
+	return strings.Join(sections, "\n")
+}
+
+func markdownNodeAnnotations(annotations *api.NodeAnnotations) string {
+	return markdownMap("Annotations", annotations.Name, annotations.Annotations)
+}
+
+func markdownNodesAnnotations(annotations []api.NodeAnnotations) string {
+	var sections []string
+	for _, a := range annotations {
+		sections = append(sections, markdownNodeAnnotations(&a))
+	}
+	return strings.Join(sections, "\n")
+}
+
+func markdownNodesConditions(nodes []api.NodeConditions) string {
+	var sections []string
+	for _, node := range nodes {
+		if len(node.Conditions) == 0 {
+			sections = append(sections, fmt.Sprintf("No conditions found for node %s\n", node.Name))
+			continue
+		}
+		var rows [][]string
+		for _, cond := range node.Conditions {
+			transition := ""
+			if cond.LastTransitionTime != nil {
+				transition = cond.LastTransitionTime.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{cond.Type, cond.Status, cond.Reason, cond.Message, transition})
+		}
+		sections = append(sections, fmt.Sprintf("### Conditions for node %s\n\n%s", node.Name,
+			markdownTable([]string{"Type", "Status", "Reason", "Message", "Last Transition"}, rows)))
+	}
+	return strings.Join(sections, "\n")
+}
+
+func markdownStrings(items []string) string {
+	return markdownList(items, "None found")
+}
+
+func markdownReleaseController(rc *api.ReleaseController) string {
+	return markdownTable([]string{"Name", "Host", "Product", "Architecture", "Aliases"},
+		[][]string{{rc.Name, markdownLink(rc.Host, rc.URL), rc.Product, rc.Architecture, strings.Join(rc.Aliases, ", ")}})
+}
+
+func markdownTag(tag *api.Tag) string {
+	return fmt.Sprintf("**%s** (%s)", markdownLink(tag.Name, tag.DownloadURL), tag.Phase)
+}
+
+func markdownTags(tags []api.Tag) string {
+	if len(tags) == 0 {
+		return "No tags found"
+	}
+	var rows [][]string
+	for _, tag := range tags {
+		rows = append(rows, []string{markdownLink(tag.Name, tag.DownloadURL), tag.Phase, tag.PullSpec})
+	}
+	return markdownTable([]string{"Name", "Phase", "Pull Spec"}, rows)
+}
+
+func markdownTagName(tag *api.Tag) string {
+	return fmt.Sprintf("**%s**", markdownLink(tag.Name, tag.DownloadURL))
+}
+
+func markdownFailedJobs(jobs []api.VerificationJob) string {
+	if len(jobs) == 0 {
+		return "No failed jobs found"
+	}
+	var rows [][]string
+	for _, job := range jobs {
+		rows = append(rows, []string{markdownLink(job.Name, job.URL), string(job.Kind), job.State, string(job.Outcome), fmt.Sprint(job.Retries)})
+	}
+	return markdownTable([]string{"Job", "Kind", "State", "Outcome", "Retries"}, rows)
+}
+
+func markdownPayloadStatus(status *api.PayloadStatus) string {
+	verdict := fmt.Sprintf("**%s** (%s): %s", status.Release, status.Phase, status.Verdict)
+	if len(status.Jobs) == 0 {
+		return verdict
+	}
+	var rows [][]string
+	for _, job := range status.Jobs {
+		rows = append(rows, []string{job.State, string(job.Kind), markdownLink(job.Name, job.URL), string(job.Outcome), fmt.Sprint(job.Retries), jobDuration(job)})
+	}
+	return verdict + "\n\n" + markdownTable([]string{"State", "Kind", "Job", "Outcome", "Retries", "Duration"}, rows)
+}
+
+func markdownStreamHealth(health *api.StreamHealth) string {
+	summary := fmt.Sprintf("**%s**: %s", health.Stream, streamAcceptance(health))
+	if len(health.Unavailable) > 0 {
+		summary += fmt.Sprintf("\n\nResults unavailable for %s", renderUnavailable(health.Unavailable))
+	}
+	if len(health.Jobs) == 0 {
+		return summary
+	}
+	var rows [][]string
+	for _, job := range health.Jobs {
+		rows = append(rows, []string{string(job.Kind), job.Name, fmt.Sprintf("%.0f%%", job.PassRate), fmt.Sprint(job.Passed), fmt.Sprint(job.Failed)})
+	}
+	return summary + "\n\n" + markdownTable([]string{"Kind", "Job", "Pass Rate", "Passed", "Failed"}, rows)
+}
+
+func markdownJobMatrix(matrix *api.JobMatrix) string {
+	if len(matrix.Payloads) == 0 {
+		return "No payloads found"
+	}
+	header := []string{"Job", "Kind"}
+	for _, payload := range matrix.Payloads {
+		column := fmt.Sprintf("%s (%s)", payload.Name, payload.Phase)
+		if !payload.Available {
+			column += " unavailable"
+		}
+		header = append(header, column)
+	}
+	header = append(header, "Failing Since")
+	var rows [][]string
+	for _, job := range matrix.Jobs {
+		row := []string{job.Name, string(job.Kind)}
+		for _, cell := range job.Cells {
+			row = append(row, markdownLink(matrixCell(cell), cell.URL))
+		}
+		rows = append(rows, append(row, job.FailingSince))
+	}
+	return fmt.Sprintf("**%s**\n\n", matrix.Stream) + markdownTable(header, rows)
+}
+
+func markdownJobBisection(b *api.JobBisection) string {
+	sections := []string{bisectionSummary(b)}
+	var runs []string
+	if b.FirstFailedURL != "" {
+		runs = append(runs, markdownLink("First failure in "+b.FirstFailed, b.FirstFailedURL))
+	}
+	if b.LastPassedURL != "" {
+		runs = append(runs, markdownLink("Last pass in "+b.LastPassed, b.LastPassedURL))
+	}
+	if len(b.Unavailable) > 0 {
+		runs = append(runs, "Results unavailable for "+renderUnavailable(b.Unavailable))
+	}
+	if len(runs) > 0 {
+		sections = append(sections, strings.TrimSuffix(markdownList(runs, ""), "\n"))
+	}
+	if b.Suspects != nil {
+		sections = append(sections, markdownReleaseComparison(b.Suspects))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+func markdownComponents(components []api.Component) string {
+	if len(components) == 0 {
+		return "No components found"
+	}
+	var rows [][]string
+	for _, component := range components {
+		rows = append(rows, []string{component.Name, component.Version})
+	}
+	return markdownTable([]string{"Component", "Version"}, rows)
+}
+
+func markdownIssues(issues []api.Issue) string {
+	var items []string
+	for _, issue := range issues {
+		items = append(items, markdownLink(issue.ID, issue.URL))
+	}
+	return markdownList(items, "No issues found in updated images commits")
+}
+
+func markdownReleaseComparison(c *api.ReleaseComparison) string {
+	var sections []string
+	sections = append(sections, fmt.Sprintf("## Changes from %s to %s", c.From, c.To))
+	var components [][]string
+	for _, component := range c.Components {
+		components = append(components, []string{component.Name, component.From, markdownLink(component.To, component.DiffURL)})
+	}
+	if len(components) > 0 {
+		sections = append(sections, "### Components\n\n"+markdownTable([]string{"Component", "From", "To"}, components))
+	}
+	var updated []string
+	for _, image := range c.UpdatedImages {
+		updated = append(updated, fmt.Sprintf("%s (%s)", image.Name, markdownLink(fmt.Sprintf("%d commits", image.Commits), image.FullChangeLog)))
+	}
+	sections = append(sections,
+		"### New images\n\n"+markdownList(c.NewImages, "None"),
+		"### Removed images\n\n"+markdownList(c.RemovedImages, "None"),
+		"### Updated images\n\n"+markdownList(updated, "None"),
+	)
+	var commits [][]string
+	for _, commit := range c.Commits {
+		commits = append(commits, []string{commit.Image, markdownLink(commit.Subject, commit.URL), strings.Join(commit.Issues, ", ")})
+	}
+	if len(commits) > 0 {
+		sections = append(sections, "### Commits\n\n"+markdownTable([]string{"Image", "Commit", "Issues"}, commits))
+	}
+	var issues []string
+	for _, issue := range c.Issues {
+		issues = append(issues, markdownLink(issue.ID, issue.URL))
+	}
+	sections = append(sections, "### Issues\n\n"+markdownList(issues, "None"))
+	return strings.Join(sections, "\n\n")
+}
+
+// markdownUpgradeEdges renders upgrade edges as a table, with a link to each run
+func markdownUpgradeEdges(edges []api.UpgradeEdge) string {
+	if len(edges) == 0 {
+		return "None tested"
+	}
+	var rows [][]string
+	for _, edge := range edges {
+		var runs []string
+		for i, run := range edge.Runs {
+			runs = append(runs, markdownLink(fmt.Sprintf("%d %s", i+1, run.State), run.URL))
+		}
+		rows = append(rows, []string{edge.From, edge.To, fmt.Sprint(edge.Success), fmt.Sprint(edge.Failure), fmt.Sprint(edge.Total), fmt.Sprintf("%.0f%%", edge.SuccessRate), strings.Join(runs, ", ")})
+	}
+	return markdownTable([]string{"From", "To", "Success", "Failure", "Total", "Success Rate", "Runs"}, rows)
+}
+
+func markdownReleaseUpgrades(upgrades *api.ReleaseUpgrades) string {
+	return fmt.Sprintf("### Upgrades to %s\n\n%s\n\n### Upgrades from %s\n\n%s", upgrades.Release, markdownUpgradeEdges(upgrades.To), upgrades.Release, markdownUpgradeEdges(upgrades.From))
+}
+
+func markdownUpgradeEdge(edge *api.UpgradeEdge) string {
+	if edge.Total == 0 {
+		return fmt.Sprintf("The upgrade from **%s** to **%s** has not been tested", edge.From, edge.To)
+	}
+	return markdownUpgradeEdges([]api.UpgradeEdge{*edge})
+}
+
+// markdownTests renders the tests of a job as a list under a heading
+func markdownTests(kind string, results *api.TestResults) string {
+	if len(results.Tests) == 0 {
+		return fmt.Sprintf("No %s tests found for %s in job", strings.ToLower(kind), results.Test)
+	}
+	var items []string
+	for _, test := range results.Tests {
+		items = append(items, "`"+strings.ReplaceAll(test, "`", "'")+"`")
+	}
+	return fmt.Sprintf("### %s tests in %s (%s)\n\n%s", kind, results.Test, results.Step, markdownList(items, ""))
+}
+
+func markdownFailingTests(results *api.TestResults) string {
+	return markdownTests("Failing", results)
+}
+
+func markdownFlakyTests(results *api.TestResults) string {
+	return markdownTests("Flaky", results)
+}
+
+func markdownRiskAnalysis(risk *api.RiskAnalysis) string {
+	return markdownCode(string(risk.Data), "json")
+}
+
+func markdownSpyglassEvents(events []api.SpyglassEvent) string {
+	if len(events) == 0 {
+		return "No error or warning events found in spyglass data"
+	}
+	var rows [][]string
+	for _, event := range events {
+		rows = append(rows, []string{event.From.Format(time.RFC3339), event.To.Format(time.RFC3339), event.Source, event.Type, event.Test, event.Reason, event.Message})
+	}
+	return markdownTable([]string{"From", "To", "Source", "Type", "Test", "Reason", "Message"}, rows)
+}
+
+func markdownJobLog(log *api.JobLog) string {
+	title := log.Path
+	if log.Step != "" {
+		title = fmt.Sprintf("%s (%s)", log.Step, log.Path)
+	}
+	return fmt.Sprintf("### %s\n\n%s", title, markdownLog(log.Log))
+}
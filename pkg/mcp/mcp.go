@@ -1,22 +1,76 @@
 package mcp
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
 	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/cluster"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/prompts"
 	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasecontroller"
 	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/version"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"slices"
 )
 
 type Server struct {
 	server            *server.MCPServer
 	releaseController releasecontroller.ReleaseController
 	cluster           cluster.Cluster
+	// maxResponseSize is the size in bytes above which tool results are split in pages, 0 for no limit
+	maxResponseSize int
+	// promptsDir is the directory of the prompt templates overriding the embedded ones
+	promptsDir string
+	// pollInterval and pollMaxBackoff configure the polling of the subscribed release streams
+	pollInterval, pollMaxBackoff time.Duration
+	subscriptions                *subscriptions
+	// toolsets, disabledTools and readOnly select the tools to register
+	toolsets, disabledTools []string
+	readOnly                bool
+	// exportDir is the directory export_release_data writes to
+	exportDir string
 }
 
-func NewSever() (*Server, error) {
+// Option configures a Server
+type Option func(*Server)
+
+// WithMaxResponseSize sets the size in bytes above which tool results are split in pages, 0 for no limit
+func WithMaxResponseSize(size int) Option {
+	return func(s *Server) {
+		s.maxResponseSize = size
+	}
+}
+
+// WithPromptsDir sets the directory of the prompt templates overriding the embedded ones
+func WithPromptsDir(dir string) Option {
+	return func(s *Server) {
+		s.promptsDir = dir
+	}
+}
+
+// WithPollInterval sets how often the release streams whose resources are subscribed to are polled
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.pollInterval = interval
+	}
+}
+
+// WithPollMaxBackoff sets the longest delay between two polls of a release stream failing to be fetched
+func WithPollMaxBackoff(backoff time.Duration) Option {
+	return func(s *Server) {
+		s.pollMaxBackoff = backoff
+	}
+}
+
+func NewSever(cfg *config.Config, opts ...Option) (*Server, error) {
 	s := &Server{
+		maxResponseSize: DefaultMaxResponseSize,
+		pollInterval:    DefaultPollInterval,
+		pollMaxBackoff:  DefaultPollMaxBackoff,
 		server: server.NewMCPServer(
 			version.BinaryName,
 			version.Version,
@@ -26,25 +80,59 @@ func NewSever() (*Server, error) {
 			server.WithLogging(),
 		),
 	}
-	s.releaseController = releasecontroller.NewReleaseController()
-	s.cluster = cluster.NewCluster()
-	s.server.AddTools(slices.Concat(
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.releaseController = releasecontroller.NewReleaseController(cfg)
+	s.cluster = cluster.NewCluster(cfg)
+	tools, err := s.selectTools(s.tools())
+	if err != nil {
+		return nil, err
+	}
+	for i := range tools {
+		tools[i].Handler = authorize(tools[i].Tool.Name, s.paginate(tools[i].Handler))
+	}
+	s.server.AddTools(tools...)
+	promptSet, err := prompts.Load(s.promptsDir)
+	if err != nil {
+		return nil, err
+	}
+	s.initPrompts(promptSet)
+	s.initResources(context.Background())
+	s.subscriptions = newSubscriptions(s.releaseController, s.pollInterval, s.pollMaxBackoff)
+	return s, nil
+}
+
+// tools returns every tool of the server, before the toolsets are applied
+func (s *Server) tools() []server.ServerTool {
+	return slices.Concat(
 		s.initReleaseController(),
 		s.initCluster(),
-	)...)
-	return s, nil
+		s.initCache(),
+		s.initExport(),
+	)
 }
 
-func (s *Server) ServeStdio() error {
-	return server.ServeStdio(s.server)
+// ServeStdio serves the MCP server on stdin and stdout until the context is done
+func (s *Server) ServeStdio(ctx context.Context) error {
+	stdioServer := server.NewStdioServer(s.server)
+	stdioServer.SetErrorLogger(log.New(os.Stderr, "", log.LstdFlags))
+	stdout := &lockedWriter{w: os.Stdout}
+	return stdioServer.Listen(ctx, interceptLines(s.subscriptions, "stdio", os.Stdin, stdout), stdout)
 }
 
-func (s *Server) ServeSse(baseUrl string) *server.SSEServer {
+// ServeSse returns the SSE transport of the MCP server
+func (s *Server) ServeSse(baseUrl string) *SSEServer {
 	options := make([]server.SSEOption, 0)
 	if baseUrl != "" {
 		options = append(options, server.WithBaseURL(baseUrl))
 	}
-	return server.NewSSEServer(s.server, options...)
+	return &SSEServer{SSEServer: server.NewSSEServer(s.server, options...), interceptor: s.subscriptions}
+}
+
+// ServeStreamableHTTP returns the streamable HTTP transport of the MCP server
+func (s *Server) ServeStreamableHTTP(opts ...StreamableHTTPOption) *StreamableHTTPServer {
+	return NewStreamableHTTPServer(s.server, append([]StreamableHTTPOption{withInterceptor(s.subscriptions)}, opts...)...)
 }
 
 func NewTextResult(content string, err error) *mcp.CallToolResult {
@@ -68,3 +156,26 @@ func NewTextResult(content string, err error) *mcp.CallToolResult {
 		},
 	}
 }
+
+// NewPagedTextResult returns a page of a result which was too large to be returned at once.
+// The page is followed by a second text content telling how to get the rest of the result.
+func NewPagedTextResult(content string, page Page) *mcp.CallToolResult {
+	notice := fmt.Sprintf("[TRUNCATED: this is bytes %d-%d of a %d byte result.", page.Offset, page.End, page.Total)
+	if page.Next != "" {
+		notice += fmt.Sprintf(" Call the tool again with the same arguments and cursor %q to get the next page.]", page.Next)
+	} else {
+		notice += " This is the last page.]"
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: content,
+			},
+			mcp.TextContent{
+				Type: "text",
+				Text: notice,
+			},
+		},
+	}
+}
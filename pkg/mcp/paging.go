@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultMaxResponseSize is the default size in bytes above which tool results are split in pages
+const DefaultMaxResponseSize = 256 * 1024
+
+// Page is a window of a tool result which was too large to be returned at once
+type Page struct {
+	// Offset and End are the byte offsets of the page in the full result
+	Offset, End int
+	// Total is the size of the full result
+	Total int
+	// Next is the cursor to pass to get the next page, empty for the last page
+	Next string
+}
+
+// withPaging adds the optional arguments to page through large results to a tool
+func withPaging() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithNumber("maxSize",
+			mcp.Description("Maximum size of the result in bytes, capped by the server limit. Larger results are split in pages."),
+			mcp.Min(1),
+		)(t)
+		mcp.WithString("cursor",
+			mcp.Description("Cursor returned by a previous call with the same arguments, to get the next page of a truncated result"),
+		)(t)
+	}
+}
+
+// withLineRange adds the optional arguments selecting the lines of a log to a tool
+func withLineRange() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithNumber("head", mcp.Description("Only return the first N lines of the log"), mcp.Min(1))(t)
+		mcp.WithNumber("tail", mcp.Description("Only return the last N lines of the log"), mcp.Min(1))(t)
+		mcp.WithString("lines", mcp.Description("Only return a range of lines of the log, e.g. 100-200 (1-based, inclusive)"))(t)
+	}
+}
+
+// paginate wraps a tool handler so that its text result is cut to the lines and the
+// page requested by the caller, and to the maximum response size of the server
+func (s *Server) paginate(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, ctr)
+		if err != nil || result == nil || result.IsError || len(result.Content) != 1 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+		content, err := selectLines(ctr, text.Text)
+		if err != nil {
+			return NewTextResult("", err), nil
+		}
+		maxSize := s.maxResponseSize
+		if n, ok := ctr.Params.Arguments["maxSize"].(float64); ok && n >= 1 && (maxSize <= 0 || int(n) < maxSize) {
+			maxSize = int(n)
+		}
+		cursor, _ := ctr.Params.Arguments["cursor"].(string)
+		content, page, err := paginate(content, cursor, maxSize)
+		if err != nil {
+			return NewTextResult("", err), nil
+		}
+		if page == nil {
+			return NewTextResult(content, nil), nil
+		}
+		return NewPagedTextResult(content, *page), nil
+	}
+}
+
+// selectLines returns the lines of the content selected with the head, tail or lines arguments
+func selectLines(ctr mcp.CallToolRequest, content string) (string, error) {
+	head, hasHead := ctr.Params.Arguments["head"].(float64)
+	tail, hasTail := ctr.Params.Arguments["tail"].(float64)
+	lineRange, _ := ctr.Params.Arguments["lines"].(string)
+	if !hasHead && !hasTail && lineRange == "" {
+		return content, nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if lineRange != "" {
+		first, last, err := parseLineRange(lineRange)
+		if err != nil {
+			return "", err
+		}
+		if first > len(lines) {
+			return "", fmt.Errorf("line %d is past the end of the log, which has %d lines", first, len(lines))
+		}
+		lines = lines[first-1 : min(last, len(lines))]
+	}
+	if hasHead && int(head) < len(lines) {
+		lines = lines[:max(int(head), 0)]
+	}
+	if hasTail && int(tail) < len(lines) {
+		lines = lines[len(lines)-max(int(tail), 0):]
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// parseLineRange parses a 1-based inclusive range of lines such as 100-200
+func parseLineRange(lineRange string) (int, int, error) {
+	from, to, found := strings.Cut(lineRange, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil || first < 1 {
+		return 0, 0, fmt.Errorf("invalid line range %q, expected e.g. 100-200", lineRange)
+	}
+	if !found {
+		return first, first, nil
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil || last < first {
+		return 0, 0, fmt.Errorf("invalid line range %q, expected e.g. 100-200", lineRange)
+	}
+	return first, last, nil
+}
+
+// paginate returns the page of the content starting at the cursor, or the whole
+// content and no page if it fits in maxSize
+func paginate(content, cursor string, maxSize int) (string, *Page, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		offset, err = parseCursor(cursor, content)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if maxSize <= 0 {
+		maxSize = len(content)
+	}
+	if offset == 0 && len(content) <= maxSize {
+		return content, nil, nil
+	}
+	end := pageEnd(content, offset, maxSize)
+	page := &Page{Offset: offset, End: end, Total: len(content)}
+	if end < len(content) {
+		page.Next = newCursor(end, content)
+	}
+	return content[offset:end], page, nil
+}
+
+// pageEnd returns the end of the page starting at offset, preferably at the end of a
+// line in the second half of the page and never in the middle of a UTF-8 sequence
+func pageEnd(content string, offset, maxSize int) int {
+	end := offset + maxSize
+	if end >= len(content) {
+		return len(content)
+	}
+	if nl := strings.LastIndexByte(content[offset:end], '\n'); nl >= maxSize/2 {
+		return offset + nl + 1
+	}
+	for end > offset+1 && !utf8.RuneStart(content[end]) {
+		end--
+	}
+	return end
+}
+
+// contentHash identifies a result so that a cursor is not used on a different one
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:6])
+}
+
+// newCursor returns an opaque cursor pointing at offset in the content
+func newCursor(offset int, content string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", offset, contentHash(content))))
+}
+
+// parseCursor returns the offset a cursor points at, checking that it was issued for the content
+func parseCursor(cursor, content string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	offsetPart, hash, _ := strings.Cut(string(data), ":")
+	offset, err := strconv.Atoi(offsetPart)
+	if err != nil || offset < 0 || offset > len(content) {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if hash != contentHash(content) {
+		return 0, fmt.Errorf("the result changed since the cursor was issued, start again without a cursor")
+	}
+	return offset, nil
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPaginateFollowsCursors(t *testing.T) {
+	var b strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	log := b.String()
+	s := &Server{maxResponseSize: 1000}
+	handler := s.paginate(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return NewTextResult(log, nil), nil
+	})
+
+	var pages []string
+	cursor := ""
+	for i := 0; i < 100; i++ {
+		ctr := callWithFormat("")
+		if cursor != "" {
+			ctr.Params.Arguments["cursor"] = cursor
+		}
+		result, _ := handler(context.Background(), ctr)
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", resultText(t, result))
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("expected a page and a truncation notice, got %d content items", len(result.Content))
+		}
+		page := result.Content[0].(mcp.TextContent).Text
+		if len(page) > 1000 || !strings.HasSuffix(page, "\n") {
+			t.Fatalf("page of %d bytes does not end at a line: %q", len(page), page)
+		}
+		pages = append(pages, page)
+		notice := result.Content[1].(mcp.TextContent).Text
+		if !strings.HasPrefix(notice, "[TRUNCATED") {
+			t.Fatalf("unexpected notice: %q", notice)
+		}
+		_, after, found := strings.Cut(notice, "cursor \"")
+		if !found {
+			break
+		}
+		cursor, _, _ = strings.Cut(after, "\"")
+	}
+	if got := strings.Join(pages, ""); got != log {
+		t.Errorf("pages do not add up to the result: got %d bytes, expected %d", len(got), len(log))
+	}
+
+	ctr := callWithFormat("")
+	ctr.Params.Arguments["cursor"] = newCursor(10, "another result")
+	if result, _ := handler(context.Background(), ctr); !result.IsError {
+		t.Errorf("expected an error for a cursor issued for another result")
+	}
+}
+
+func TestSelectLines(t *testing.T) {
+	log := "a\nb\nc\nd\ne\n"
+	for _, tc := range []struct {
+		args     map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{}, log},
+		{map[string]interface{}{"head": float64(2)}, "a\nb\n"},
+		{map[string]interface{}{"tail": float64(2)}, "d\ne\n"},
+		{map[string]interface{}{"lines": "2-3"}, "b\nc\n"},
+		{map[string]interface{}{"lines": "4-100"}, "d\ne\n"},
+		{map[string]interface{}{"lines": "2-4", "tail": float64(1)}, "d\n"},
+	} {
+		var ctr mcp.CallToolRequest
+		ctr.Params.Arguments = tc.args
+		got, err := selectLines(ctr, log)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.args, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%v: got %q, expected %q", tc.args, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The functions below are the text summaries the pod tools returned before their results
+// were structured, the text renderers must keep printing the same lines.
+func goldenAllPodsSummary(pods []corev1.Pod) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		fmt.Fprintf(&b, "%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName, pod.Status.Phase)
+	}
+	return b.String()
+}
+
+func goldenRunningPodsSummary(pods []corev1.Pod) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			running := true
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.State.Running != nil {
+					continue
+				} else {
+					running = false
+					break
+				}
+			}
+			if running {
+				fmt.Fprintf(&b, "%s/%s on %s: Running\n", pod.Namespace, pod.Name, pod.Spec.NodeName)
+			}
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return "No pods in Running state."
+	}
+}
+
+func goldenCrashLoopBackOffSummary(pods []corev1.Pod) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+				fmt.Fprintf(&b, "%s/%s on %s: %s (%s)\n", pod.Namespace, pod.Name, pod.Spec.NodeName, cs.State.Waiting.Reason, cs.State.Waiting.Message)
+			}
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return "No pods in CrashLoopBackOff state."
+	}
+}
+
+func goldenPendingPodsSummary(pods []corev1.Pod) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodPending {
+			reason := pod.Status.Reason
+			if reason == "" {
+				// Fallback: Try to get reason from pod conditions
+				for _, cond := range pod.Status.Conditions {
+					if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+						reason = cond.Reason
+						break
+					}
+				}
+			}
+			if reason == "" {
+				reason = "Unknown"
+			}
+			fmt.Fprintf(&b, "%s/%s on %s: Pending (%s)\n", pod.Namespace, pod.Name, pod.Spec.NodeName, reason)
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return "No pods in Pending state."
+	}
+}
+
+func goldenInitStateSummary(pods []corev1.Pod) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		for _, cs := range pod.Status.InitContainerStatuses {
+			if cs.State.Waiting != nil && strings.Contains(cs.State.Waiting.Reason, "Init") {
+				fmt.Fprintf(&b, "%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName, cs.State.Waiting.Reason)
+				break
+			}
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return "No pods in Init state."
+	}
+}
+
+func goldenErrorStateSummary(pods []corev1.Pod) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "Error" {
+				fmt.Fprintf(&b, "%s/%s: %s\n", pod.Namespace, pod.Name, cs.State.Waiting.Reason)
+				break
+			}
+			if cs.State.Terminated != nil && cs.State.Terminated.Reason == "Error" {
+				fmt.Fprintf(&b, "%s/%s: %s\n", pod.Namespace, pod.Name, cs.State.Terminated.Reason)
+				break
+			}
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return "No pods in Error state."
+	}
+}
+
+func goldenFilterPodsByNamespaceAsString(pods []corev1.Pod, namespace string) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		if pod.Namespace == namespace {
+			fmt.Fprintf(&b, "%s/%s on %s: ", pod.Namespace, pod.Name, pod.Spec.NodeName)
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.State.Waiting != nil && (cs.State.Waiting.Reason == "Error" || cs.State.Waiting.Reason == "CrashLoopBackOff" || cs.State.Waiting.Reason == "Init") {
+					fmt.Fprintf(&b, "%s %s\n", cs.State.Waiting.Reason, cs.State.Waiting.Message)
+					break
+				}
+				if cs.State.Terminated != nil && cs.State.Terminated.Reason == "Error" {
+					fmt.Fprintf(&b, "%s %s\n", cs.State.Terminated.Reason, cs.State.Terminated.Message)
+					break
+				}
+			}
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return fmt.Sprintf("No pods found in namespace %s.", namespace)
+	}
+}
+
+func goldenFilterPodsByNodeAsString(pods []corev1.Pod, nodeName string) string {
+	var b strings.Builder
+	for _, pod := range pods {
+		if pod.Spec.NodeName == nodeName {
+			fmt.Fprintf(&b, "%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName, pod.Status.Phase)
+		}
+	}
+	if b.Len() > 0 {
+		return b.String()
+	} else {
+		return fmt.Sprintf("No pods found on node %s.", nodeName)
+	}
+}
+
+func goldenPods() []corev1.Pod {
+	waiting := func(name, reason, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}}}
+	}
+	running := corev1.ContainerStatus{Name: "main", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	pod := func(namespace, name, node string, phase corev1.PodPhase, status corev1.PodStatus) corev1.Pod {
+		status.Phase = phase
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}, Spec: corev1.PodSpec{NodeName: node}, Status: status}
+	}
+	return []corev1.Pod{
+		pod("openshift-etcd", "etcd-0", "master-0", corev1.PodRunning, corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{running}}),
+		pod("openshift-etcd", "etcd-1", "master-1", corev1.PodRunning, corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			running, waiting("sidecar", "CrashLoopBackOff", "back-off 5m0s"), waiting("proxy", "CrashLoopBackOff", "back-off 10s"),
+		}}),
+		pod("openshift-etcd", "etcd-2", "master-2", corev1.PodRunning, corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "main", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", Message: "exit 1"}}},
+		}}),
+		pod("openshift-etcd", "etcd-guard", "master-0", corev1.PodFailed, corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{waiting("guard", "Error", "failed")}}),
+		pod("openshift-ingress", "router-0", "", corev1.PodPending, corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable"},
+		}}),
+		pod("openshift-ingress", "router-1", "worker-0", corev1.PodPending, corev1.PodStatus{Reason: "NodeLost"}),
+		pod("openshift-ingress", "router-2", "worker-1", corev1.PodPending, corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{waiting("setup", "Init:CrashLoopBackOff", "")},
+		}),
+	}
+}
+
+func TestRenderPodsLikeBefore(t *testing.T) {
+	pods := goldenPods()
+	for _, tc := range []struct {
+		name     string
+		expected func([]corev1.Pod) string
+		rendered func([]corev1.Pod) string
+	}{
+		{"all", goldenAllPodsSummary, func(pods []corev1.Pod) string { return renderPodsInState(utils.AllPodsSummary(pods), "All") }},
+		{"running", goldenRunningPodsSummary, func(pods []corev1.Pod) string { return renderPodsInState(utils.RunningPodsSummary(pods), "Running") }},
+		{"crashloop", goldenCrashLoopBackOffSummary, func(pods []corev1.Pod) string {
+			return renderPodsInState(utils.CrashLoopBackOffSummary(pods), "CrashLoopBackOff")
+		}},
+		{"pending", goldenPendingPodsSummary, func(pods []corev1.Pod) string { return renderPodsInState(utils.PendingPodsSummary(pods), "Pending") }},
+		{"init", goldenInitStateSummary, func(pods []corev1.Pod) string { return renderPodsInState(utils.InitStateSummary(pods), "Init") }},
+		{"error", goldenErrorStateSummary, func(pods []corev1.Pod) string { return renderPodsInState(utils.ErrorStateSummary(pods), "Error") }},
+		{"namespace", func(pods []corev1.Pod) string { return goldenFilterPodsByNamespaceAsString(pods, "openshift-etcd") }, func(pods []corev1.Pod) string {
+			return renderPodsInNamespace(utils.FilterPodsByNamespace(pods, "openshift-etcd"), "openshift-etcd")
+		}},
+		{"node", func(pods []corev1.Pod) string { return goldenFilterPodsByNodeAsString(pods, "master-0") }, func(pods []corev1.Pod) string {
+			return renderPodsInNode(utils.FilterPodsByNode(pods, "master-0"), "master-0")
+		}},
+	} {
+		for _, pods := range [][]corev1.Pod{pods, pods[:1], nil} {
+			if expected, rendered := tc.expected(pods), tc.rendered(pods); rendered != expected {
+				t.Errorf("%s: expected %q, got %q", tc.name, expected, rendered)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/prompts"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// initPrompts registers the prompts of the set with the MCP server
+func (s *Server) initPrompts(set *prompts.Set) {
+	for _, p := range set.Prompts() {
+		options := []mcp.PromptOption{mcp.WithPromptDescription(promptDescription(p))}
+		for _, arg := range p.Arguments {
+			argOptions := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+			if arg.Required {
+				argOptions = append(argOptions, mcp.RequiredArgument())
+			}
+			options = append(options, mcp.WithArgument(arg.Name, argOptions...))
+		}
+		s.server.AddPrompt(mcp.NewPrompt(p.Name, options...), authorizePrompt(p.Name, promptHandler(set, p)))
+	}
+}
+
+// promptDescription tells the version of the template and where it comes from, so that
+// the prompts overridden from the prompts directory can be told apart
+func promptDescription(p *prompts.Prompt) string {
+	if p.Source != "" {
+		return fmt.Sprintf("%s (template v%d from %s)", p.Description, p.Version, p.Source)
+	}
+	return fmt.Sprintf("%s (template v%d)", p.Description, p.Version)
+}
+
+func promptHandler(set *prompts.Set, p *prompts.Prompt) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text, err := set.Render(p.Name, request.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult(p.Description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	}
+}
@@ -2,116 +2,216 @@ package mcp
 
 import (
 	"context"
+	"slices"
+	"strings"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// withReleaseController adds the releasecontroller argument of the tools working on a release stream
+func withReleaseController() mcp.ToolOption {
+	return mcp.WithString("releasecontroller",
+		mcp.Description("The release controller to query: its host, name or an alias such as okd, ocp, multi, arm64, aarch64 or \"4.20 ppc\""),
+		mcp.Required(),
+	)
+}
+
 // Register the CLI tools for the release controller.
 func (s *Server) initReleaseController() []server.ServerTool {
 	return []server.ServerTool{
-		{mcp.NewTool("list_release_controllers",
-			mcp.WithDescription("Lists the available release controllers to use. Only two are available - OKD and OpenShift."),
-		), s.listReleaseControllers},
-		{mcp.NewTool("get_okd_release_controller",
-			mcp.WithDescription("Gets the OKD/origin release controller URL."),
-		), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return NewTextResult(s.releaseController.GetOKDReleaseController(), nil), nil
-		}},
-		{mcp.NewTool("get_ocp_release_controller",
-			mcp.WithDescription("Gets the OpenShift/OCP/ocp release controller URL."),
-		), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return NewTextResult(s.releaseController.GetOCPReleaseController(), nil), nil
-		}},
-		{mcp.NewTool("get_multi_release_controller",
-			mcp.WithDescription("Gets the multi-arch/multi release controller URL."),
-		), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return NewTextResult(s.releaseController.GetMultiReleaseController(), nil), nil
-		}},
-		{mcp.NewTool("get_arm64_release_controller",
-			mcp.WithDescription("Gets the ARM64/arm64 release controller URL."),
-		), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return NewTextResult(s.releaseController.GetARM64ReleaseController(), nil), nil
-		}},
-		{mcp.NewTool("get_ppc64le_release_controller",
-			mcp.WithDescription("Gets the PPC64LE/ppc64le release controller URL."),
-		), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return NewTextResult(s.releaseController.GetPPC64LEReleaseController(), nil), nil
-		}},
-		{mcp.NewTool("get_s390x_release_controller",
-			mcp.WithDescription("Gets the S390X/s390x release controller URL."),
-		), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return NewTextResult(s.releaseController.GetS390XReleaseController(), nil), nil
-		}},
-		{mcp.NewTool("list_release_streams",
+		{Tool: mcp.NewTool("list_release_controllers",
+			mcp.WithDescription("Lists the hosts of the available release controllers. Use resolve_release_controller to find the one of a product or architecture."),
+			withFormat(),
+			withPaging(),
+		), Handler: s.listReleaseControllers},
+		{Tool: mcp.NewTool("resolve_release_controller",
+			mcp.WithDescription("Finds the release controller for a product, architecture or alias such as okd, origin, ocp, multi, arm, aarch64, ppc64le, s390x or \"4.20 ppc\", and returns its name, host, product and architecture."),
+			mcp.WithString("alias", mcp.Description("The name, host or alias of the release controller"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: s.resolveReleaseController},
+		{Tool: mcp.NewTool("list_release_streams",
 			mcp.WithDescription("Lists all the release streams in the release controller."),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
-		), s.listReleaseStreams},
-		{mcp.NewTool("latest_release",
+			withReleaseController(),
+			withFormat(),
+			withPaging(),
+		), Handler: s.listReleaseStreams},
+		{Tool: mcp.NewTool("latest_release",
 			mcp.WithDescription("Gets the latest release for a given release stream."),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
-		), s.latestReleaseWithPhase},
-		{mcp.NewTool("latest_accepted_release",
+			withFormat(),
+			withPaging(),
+		), Handler: s.latestReleaseWithPhase},
+		{Tool: mcp.NewTool("latest_accepted_release",
 			mcp.WithDescription("Gets the latest accepted release for a given release stream."),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
-		), s.latestAcceptedRelease},
-		{mcp.NewTool("latest_rejected_release",
+			withFormat(),
+			withPaging(),
+		), Handler: s.latestAcceptedRelease},
+		{Tool: mcp.NewTool("latest_rejected_release",
 			mcp.WithDescription("Gets the latest rejected release for a given release stream."),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
-		), s.latestRejectedRelease},
-		{mcp.NewTool("list_failed_jobs_in_release",
+			withFormat(),
+			withPaging(),
+		), Handler: s.latestRejectedRelease},
+		{Tool: mcp.NewTool("list_recent_tags",
+			mcp.WithDescription("Lists the most recent tags of a release stream with their phase, newest first, optionally only the ones in some phases."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithNumber("count", mcp.Description("The number of tags to list, 10 by default"), mcp.Min(1)),
+			mcp.WithString("phase", mcp.Description("Only list the tags in these phases, comma separated: Accepted, Rejected, Ready or Failed")),
+			withFormat(),
+			withPaging(),
+		), Handler: s.listRecentTags},
+		{Tool: mcp.NewTool("list_failed_jobs_in_release",
 			mcp.WithDescription("Lists all the failed jobs in a given release along with the prow job URL."),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
 			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
-		), s.listFailedJobsInRelease},
-		{mcp.NewTool("list_components_in_release",
+			withFormat(),
+			withPaging(),
+		), Handler: s.listFailedJobsInRelease},
+		{Tool: mcp.NewTool("get_payload_status",
+			mcp.WithDescription("Gets every verification job of a release payload grouped by state (Failed, Pending, Succeeded) and kind (blocking, informing), how long the pending ones have been running, and whether the payload can still be accepted. Use it for payloads which are still Ready."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
+			mcp.WithString("outcome", mcp.Description("Only list the jobs with these outcomes, comma separated: passed_first_try, passed_after_retry, failed, exhausted_retries, pending or retrying")),
+			withFormat(),
+			withPaging(),
+		), Handler: s.getPayloadStatus},
+		{Tool: mcp.NewTool("get_stream_health",
+			mcp.WithDescription("Gets the health of a release stream over its most recent payloads: the acceptance rate, the longest and current rejection streaks, when a payload was last accepted, and the pass rate of every blocking and informing job, the least reliable first."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithNumber("count", mcp.Description("The number of payloads to look at, 20 by default"), mcp.Min(1), mcp.Max(100)),
+			withFormat(),
+			withPaging(),
+		), Handler: s.getStreamHealth},
+		{Tool: mcp.NewTool("get_job_matrix",
+			mcp.WithDescription("Gets the state and prow job URL of every verification job (rows) in the most recent payloads of a release stream (columns, newest first), as on the release controller stream page. Use it to find the payload at which a job started failing."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithNumber("count", mcp.Description("The number of payloads to look at, 10 by default"), mcp.Min(1), mcp.Max(50)),
+			withFormat(),
+			withPaging(),
+		), Handler: s.getJobMatrix},
+		{Tool: mcp.NewTool("bisect_job_failure",
+			mcp.WithDescription("Finds the first payload of the current failure streak of a verification job in a release stream and the last payload in which it passed, and lists the changes between the two (component bumps, updated images and pull requests) as suspects."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithString("job", mcp.Description("The verification job name, as listed by get_payload_status or get_job_matrix"), mcp.Required()),
+			mcp.WithNumber("count", mcp.Description("The number of payloads to look back through, 20 by default"), mcp.Min(1), mcp.Max(100)),
+			withFormat(),
+			withPaging(),
+		), Handler: s.bisectJobFailure},
+		{Tool: mcp.NewTool("list_components_in_release",
 			mcp.WithDescription("Lists the kubectl, kubernetes, coreos and tests versions in the release."),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
 			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
-		), s.listComponentsInRelease},
-		{mcp.NewTool("list_test_failures_for_release",
+			withFormat(),
+			withPaging(),
+		), Handler: s.listComponentsInRelease},
+		{Tool: mcp.NewTool("compare_releases",
+			mcp.WithDescription("Compares two releases, which do not need to be consecutive, e.g. 4.19.3 and 4.19.7. Lists the component version changes, the new, removed and updated images, and all the commits and issues between them."),
+			withReleaseController(),
+			mcp.WithString("from", mcp.Description("The older release tag"), mcp.Required()),
+			mcp.WithString("to", mcp.Description("The newer release tag"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+			from := ctr.Params.Arguments["from"].(string)
+			to := ctr.Params.Arguments["to"].(string)
+			result, err := s.releaseController.CompareReleases(ctx, releasecontroller, from, to)
+			return NewRenderedResult(ctr, result, err, renderReleaseComparison, markdownReleaseComparison), nil
+		}},
+		{Tool: mcp.NewTool("list_upgrades_for_release",
+			mcp.WithDescription("Lists the upgrades tested into and out of a release, with their success, failure and total run counts and the links to the upgrade job runs."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
+			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+			stream := ctr.Params.Arguments["stream"].(string)
+			tag := ctr.Params.Arguments["tag"].(string)
+			result, err := s.releaseController.ListUpgradesForRelease(ctx, releasecontroller, stream, tag)
+			return NewRenderedResult(ctr, result, err, renderReleaseUpgrades, markdownReleaseUpgrades), nil
+		}},
+		{Tool: mcp.NewTool("get_upgrade_edge",
+			mcp.WithDescription("Tells whether the upgrade from a release to another one has been tested and how reliable it is, with the success rate and the links to the upgrade job runs."),
+			withReleaseController(),
+			mcp.WithString("stream", mcp.Description("The release stream of the release upgraded to"), mcp.Required()),
+			mcp.WithString("from", mcp.Description("The release upgraded from"), mcp.Required()),
+			mcp.WithString("to", mcp.Description("The release upgraded to"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+			stream := ctr.Params.Arguments["stream"].(string)
+			from := ctr.Params.Arguments["from"].(string)
+			to := ctr.Params.Arguments["to"].(string)
+			result, err := s.releaseController.GetUpgradeEdge(ctx, releasecontroller, stream, from, to)
+			return NewRenderedResult(ctr, result, err, renderUpgradeEdge, markdownUpgradeEdge), nil
+		}},
+		{Tool: mcp.NewTool("list_test_failures_for_release",
 			mcp.WithDescription("Gets the failing tests for the particular job. List the failing tests in the release if there are any. If there are no failing tests, return a message saying so."),
-			mcp.WithString("prowurl", mcp.Description("The prow job URL"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			mcp.WithString("prowurl", mcp.Description("The prow job URL, or a local artifact root (directory or .tar.gz) of a downloaded job"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			prowurl := ctr.Params.Arguments["prowurl"].(string)
-			result, err := s.releaseController.ListTestFailuresForRelease(prowurl)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.ListTestFailuresForRelease(ctx, prowurl)
+			return NewRenderedResult(ctr, result, err, renderFailingTests, markdownFailingTests), nil
 		}},
-		{mcp.NewTool("get_flaky_tests_for_release",
+		{Tool: mcp.NewTool("get_flaky_tests_for_release",
 			mcp.WithDescription("Gets the flaky tests for the particular job. List the flaky tests in the release if there are any. If there are no flaky tests, return a message saying so."),
-			mcp.WithString("prowurl", mcp.Description("The prow job URL"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			mcp.WithString("prowurl", mcp.Description("The prow job URL, or a local artifact root (directory or .tar.gz) of a downloaded job"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			prowurl := ctr.Params.Arguments["prowurl"].(string)
-			result, err := s.releaseController.GetFlakyTestsForRelease(prowurl)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.GetFlakyTestsForRelease(ctx, prowurl)
+			return NewRenderedResult(ctr, result, err, renderFlakyTests, markdownFlakyTests), nil
 		}},
-		{mcp.NewTool("get_risk_analysis_data",
+		{Tool: mcp.NewTool("get_risk_analysis_data",
 			mcp.WithDescription("Gets the risk analysis data for the particular job. List the risk analysis data in the release if there are any. If there is no risk analysis data, return a message saying so."),
-			mcp.WithString("prowurl", mcp.Description("The prow job URL"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			mcp.WithString("prowurl", mcp.Description("The prow job URL, or a local artifact root (directory or .tar.gz) of a downloaded job"), mcp.Required()),
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			prowurl := ctr.Params.Arguments["prowurl"].(string)
-			result, err := s.releaseController.GetRiskAnalysisData(prowurl)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.GetRiskAnalysisData(ctx, prowurl)
+			return NewRenderedResult(ctr, result, err, renderRiskAnalysis, markdownRiskAnalysis), nil
 		}},
-		{mcp.NewTool("get_spyglass_data_relevant_to_test_failure",
+		{Tool: mcp.NewTool("get_spyglass_data_relevant_to_test_failure",
 			mcp.WithDescription("Gets the spyglass data relevant to a test failure. Contains information about the error and warning events including timestamp"),
-			mcp.WithString("prowurl", mcp.Description("The prow job URL"), mcp.Required()),
+			mcp.WithString("prowurl", mcp.Description("The prow job URL, or a local artifact root (directory or .tar.gz) of a downloaded job"), mcp.Required()),
 			mcp.WithString("testName", mcp.Description("The test name to get the spyglass data for"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			prowurl := ctr.Params.Arguments["prowurl"].(string)
 			testName := ctr.Params.Arguments["testName"].(string)
-			result, err := s.releaseController.GetSpyglassDataRelevantToTestFailure(prowurl, testName)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.GetSpyglassDataRelevantToTestFailure(ctx, prowurl, testName)
+			return NewRenderedResult(ctr, result, err, renderSpyglassEvents, markdownSpyglassEvents), nil
 		}},
-		{mcp.NewTool("get_top_level_build_log",
+		{Tool: mcp.NewTool("get_top_level_build_log",
 			mcp.WithDescription("Gets the top-level build log for a given Prow job URL. If the log is too big, ask for compaction threshold string which can be aggresive, moderate or conservative."),
-			mcp.WithString("prowurl", mcp.Description("The prow job URL"), mcp.Required()),
+			mcp.WithString("prowurl", mcp.Description("The prow job URL, or a local artifact root (directory or .tar.gz) of a downloaded job"), mcp.Required()),
 			mcp.WithString("LogCompactionThreshold", mcp.Description("The log compaction threshold string")),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			withFormat(),
+			withPaging(),
+			withLineRange(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var logCompactionThreshold string
 			prowurl := ctr.Params.Arguments["prowurl"].(string)
 			if strVal, ok := ctr.Params.Arguments["LogCompactionThreshold"].(string); !ok {
@@ -119,101 +219,188 @@ func (s *Server) initReleaseController() []server.ServerTool {
 			} else {
 				logCompactionThreshold = strVal
 			}
-			result, err := s.releaseController.GetTopLevelBuildLog(prowurl, logCompactionThreshold)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.GetTopLevelBuildLog(ctx, prowurl, logCompactionThreshold)
+			return NewRenderedResult(ctr, result, err, renderJobLog, markdownJobLog), nil
 		}},
-		{mcp.NewTool("analyze_job_failures_for_release",
+		{Tool: mcp.NewTool("analyze_job_failures_for_release",
 			mcp.WithDescription("Gets the build log file for the particular job. Analyze the job information and look for failures. Print a short summary with relevant errors. If the log is too big, ask for compaction threshold string which can be aggresive, moderate or conservative."),
-			mcp.WithString("prowurl", mcp.Description("The prow job URL"), mcp.Required()),
+			mcp.WithString("prowurl", mcp.Description("The prow job URL, or a local artifact root (directory or .tar.gz) of a downloaded job"), mcp.Required()),
 			mcp.WithString("LogCompactionThreshold", mcp.Description("The log compaction threshold string")),
-		), s.analyzeJobFailuresForRelease},
-		{mcp.NewTool("list_features_from_updated_images_commits",
+			withFormat(),
+			withPaging(),
+			withLineRange(),
+		), Handler: s.analyzeJobFailuresForRelease},
+		{Tool: mcp.NewTool("list_features_from_updated_images_commits",
 			mcp.WithDescription("Lists issues which are features from updated images commits - excludes OCPBUGS/CVEs"),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
 			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 			stream := ctr.Params.Arguments["stream"].(string)
 			tag := ctr.Params.Arguments["tag"].(string)
-			result, err := s.releaseController.ListFeaturesFromUpdatedImagesCommits(releasecontroller, stream, tag)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.ListFeaturesFromUpdatedImagesCommits(ctx, releasecontroller, stream, tag)
+			return NewRenderedResult(ctr, result, err, renderIssues, markdownIssues), nil
 		}},
-		{mcp.NewTool("list_bugs_from_updated_images_commits",
+		{Tool: mcp.NewTool("list_bugs_from_updated_images_commits",
 			mcp.WithDescription("Lists issues which are bugs from updated images commits"),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
 			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 			stream := ctr.Params.Arguments["stream"].(string)
 			tag := ctr.Params.Arguments["tag"].(string)
-			result, err := s.releaseController.ListBugsFromUpdatedImagesCommits(releasecontroller, stream, tag)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.ListBugsFromUpdatedImagesCommits(ctx, releasecontroller, stream, tag)
+			return NewRenderedResult(ctr, result, err, renderIssues, markdownIssues), nil
 		}},
-		{mcp.NewTool("list_cves_from_updated_images_commits",
+		{Tool: mcp.NewTool("list_cves_from_updated_images_commits",
 			mcp.WithDescription("Lists issues which are CVEs from updated images commits"),
-			mcp.WithString("releasecontroller", mcp.Description("The release controller host to query"), mcp.Required()),
+			withReleaseController(),
 			mcp.WithString("stream", mcp.Description("The release stream name"), mcp.Required()),
 			mcp.WithString("tag", mcp.Description("The release tag"), mcp.Required()),
-		), func(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			withFormat(),
+			withPaging(),
+		), Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 			stream := ctr.Params.Arguments["stream"].(string)
 			tag := ctr.Params.Arguments["tag"].(string)
-			result, err := s.releaseController.ListCVEsFromUpdatedImagesCommits(releasecontroller, stream, tag)
-			return NewTextResult(result, err), nil
+			result, err := s.releaseController.ListCVEsFromUpdatedImagesCommits(ctx, releasecontroller, stream, tag)
+			return NewRenderedResult(ctr, result, err, renderIssues, markdownIssues), nil
 		}},
 	}
 }
 
-func (s *Server) listReleaseControllers(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	return NewTextResult(s.releaseController.ListReleaseControllers(), nil), nil
+func (s *Server) listReleaseControllers(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return NewRenderedResult(ctr, s.releaseController.ListReleaseControllers(ctx), nil, func(hosts []string) string {
+		return strings.Join(hosts, ",")
+	}, markdownStrings), nil
+}
+
+func (s *Server) resolveReleaseController(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	alias, _ := ctr.Params.Arguments["alias"].(string)
+	result, err := s.releaseController.ResolveReleaseController(ctx, alias)
+	return NewRenderedResult(ctr, result, err, renderReleaseController, markdownReleaseController), nil
+}
+
+func (s *Server) listRecentTags(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+	stream := ctr.Params.Arguments["stream"].(string)
+	count := 10
+	if n, ok := ctr.Params.Arguments["count"].(float64); ok && n >= 1 {
+		count = int(n)
+	}
+	var phases []string
+	if phase, ok := ctr.Params.Arguments["phase"].(string); ok {
+		for _, p := range strings.Split(phase, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				phases = append(phases, p)
+			}
+		}
+	}
+	result, err := s.releaseController.ListRecentTags(ctx, releasecontroller, stream, count, phases)
+	return NewRenderedResult(ctr, result, err, renderTags, markdownTags), nil
+}
+
+func (s *Server) getPayloadStatus(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+	stream := ctr.Params.Arguments["stream"].(string)
+	tag := ctr.Params.Arguments["tag"].(string)
+	result, err := s.releaseController.GetPayloadStatus(ctx, releasecontroller, stream, tag)
+	if outcome, _ := ctr.Params.Arguments["outcome"].(string); err == nil && outcome != "" {
+		var outcomes []api.JobOutcome
+		for _, o := range strings.Split(outcome, ",") {
+			outcomes = append(outcomes, api.JobOutcome(strings.TrimSpace(o)))
+		}
+		result.Jobs = slices.DeleteFunc(result.Jobs, func(job api.PayloadJob) bool { return !slices.Contains(outcomes, job.Outcome) })
+	}
+	return NewRenderedResult(ctr, result, err, renderPayloadStatus, markdownPayloadStatus), nil
+}
+
+func (s *Server) getStreamHealth(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+	stream := ctr.Params.Arguments["stream"].(string)
+	count := 20
+	if n, ok := ctr.Params.Arguments["count"].(float64); ok && n >= 1 {
+		count = min(int(n), 100)
+	}
+	result, err := s.releaseController.GetStreamHealth(ctx, releasecontroller, stream, count)
+	return NewRenderedResult(ctr, result, err, renderStreamHealth, markdownStreamHealth), nil
+}
+
+func (s *Server) getJobMatrix(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+	stream := ctr.Params.Arguments["stream"].(string)
+	count := 10
+	if n, ok := ctr.Params.Arguments["count"].(float64); ok && n >= 1 {
+		count = min(int(n), 50)
+	}
+	result, err := s.releaseController.GetJobMatrix(ctx, releasecontroller, stream, count)
+	return NewRenderedResult(ctr, result, err, renderJobMatrix, markdownJobMatrix), nil
+}
+
+func (s *Server) bisectJobFailure(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
+	stream := ctr.Params.Arguments["stream"].(string)
+	job := ctr.Params.Arguments["job"].(string)
+	count := 20
+	if n, ok := ctr.Params.Arguments["count"].(float64); ok && n >= 1 {
+		count = min(int(n), 100)
+	}
+	result, err := s.releaseController.BisectJobFailure(ctx, releasecontroller, stream, job, count)
+	return NewRenderedResult(ctr, result, err, renderJobBisection, markdownJobBisection), nil
 }
 
-func (s *Server) listReleaseStreams(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) listReleaseStreams(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
-	result, err := s.releaseController.ListReleaseStreams(releasecontroller)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.ListReleaseStreams(ctx, releasecontroller)
+	return NewRenderedResult(ctr, result, err, func(streams []string) string {
+		return strings.Join(streams, ", ")
+	}, markdownStrings), nil
 }
 
-func (s *Server) latestReleaseWithPhase(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) latestReleaseWithPhase(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 	stream := ctr.Params.Arguments["stream"].(string)
-	result, err := s.releaseController.LatestReleaseWithPhase(releasecontroller, stream)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.LatestReleaseWithPhase(ctx, releasecontroller, stream)
+	return NewRenderedResult(ctr, result, err, renderTag, markdownTag), nil
 }
 
-func (s *Server) latestAcceptedRelease(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) latestAcceptedRelease(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 	stream := ctr.Params.Arguments["stream"].(string)
-	result, err := s.releaseController.LatestAcceptedRelease(releasecontroller, stream)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.LatestAcceptedRelease(ctx, releasecontroller, stream)
+	return NewRenderedResult(ctr, result, err, renderTagName, markdownTagName), nil
 }
 
-func (s *Server) latestRejectedRelease(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) latestRejectedRelease(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 	stream := ctr.Params.Arguments["stream"].(string)
-	result, err := s.releaseController.LatestRejectedRelease(releasecontroller, stream)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.LatestRejectedRelease(ctx, releasecontroller, stream)
+	return NewRenderedResult(ctr, result, err, renderTagName, markdownTagName), nil
 }
 
-func (s *Server) listFailedJobsInRelease(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) listFailedJobsInRelease(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 	stream := ctr.Params.Arguments["stream"].(string)
 	tag := ctr.Params.Arguments["tag"].(string)
-	result, err := s.releaseController.ListFailedJobsInRelease(releasecontroller, stream, tag)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.ListFailedJobsInRelease(ctx, releasecontroller, stream, tag)
+	return NewRenderedResult(ctr, result, err, renderFailedJobs, markdownFailedJobs), nil
 }
 
-func (s *Server) listComponentsInRelease(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) listComponentsInRelease(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	releasecontroller := ctr.Params.Arguments["releasecontroller"].(string)
 	stream := ctr.Params.Arguments["stream"].(string)
 	tag := ctr.Params.Arguments["tag"].(string)
-	result, err := s.releaseController.ListComponentsInRelease(releasecontroller, stream, tag)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.ListComponentsInRelease(ctx, releasecontroller, stream, tag)
+	return NewRenderedResult(ctr, result, err, renderComponents, markdownComponents), nil
 }
 
-func (s *Server) analyzeJobFailuresForRelease(_ context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) analyzeJobFailuresForRelease(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var logCompactionThreshold string
 	prowurl := ctr.Params.Arguments["prowurl"].(string)
 	if strVal, ok := ctr.Params.Arguments["LogCompactionThreshold"].(string); !ok {
@@ -221,6 +408,6 @@ func (s *Server) analyzeJobFailuresForRelease(_ context.Context, ctr mcp.CallToo
 	} else {
 		logCompactionThreshold = strVal
 	}
-	result, err := s.releaseController.AnalyzeJobFailuresForRelease(prowurl, logCompactionThreshold)
-	return NewTextResult(result, err), nil
+	result, err := s.releaseController.AnalyzeJobFailuresForRelease(ctx, prowurl, logCompactionThreshold)
+	return NewRenderedResult(ctr, result, err, renderJobLog, markdownJobLog), nil
 }
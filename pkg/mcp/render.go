@@ -0,0 +1,585 @@
+package mcp
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+)
+
+// Text renderers for the results returned by the ReleaseController and Cluster interfaces
+
+// renderPods renders a line per pod, or empty if there are none
+func renderPods(pods []api.PodSummary, empty string, line func(pod api.PodSummary) string) string {
+	if len(pods) == 0 {
+		return empty
+	}
+	var b strings.Builder
+	for _, pod := range pods {
+		b.WriteString(line(pod))
+	}
+	return b.String()
+}
+
+// renderPodPhase renders a pod with its node and phase
+func renderPodPhase(pod api.PodSummary) string {
+	return fmt.Sprintf("%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Node, pod.Phase)
+}
+
+// renderPodsInState renders the pods found in a state, each state having its own line format
+func renderPodsInState(pods []api.PodSummary, state string) string {
+	switch state {
+	case "CrashLoopBackOff":
+		return renderPods(pods, "No pods in CrashLoopBackOff state.", func(pod api.PodSummary) string {
+			return fmt.Sprintf("%s/%s on %s: %s (%s)\n", pod.Namespace, pod.Name, pod.Node, pod.Reason, pod.Message)
+		})
+	case "Pending":
+		return renderPods(pods, "No pods in Pending state.", func(pod api.PodSummary) string {
+			return fmt.Sprintf("%s/%s on %s: Pending (%s)\n", pod.Namespace, pod.Name, pod.Node, pod.Reason)
+		})
+	case "Init":
+		return renderPods(pods, "No pods in Init state.", func(pod api.PodSummary) string {
+			return fmt.Sprintf("%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Node, pod.Reason)
+		})
+	case "Error":
+		return renderPods(pods, "No pods in Error state.", func(pod api.PodSummary) string {
+			return fmt.Sprintf("%s/%s: %s\n", pod.Namespace, pod.Name, pod.Reason)
+		})
+	case "Running":
+		return renderPods(pods, "No pods in Running state.", renderPodPhase)
+	default:
+		return renderPods(pods, "", renderPodPhase)
+	}
+}
+
+// renderPodsInNamespace renders the pods of a namespace, with the reason and message of
+// their failing container. The line of a pod without one is left unterminated.
+func renderPodsInNamespace(pods []api.PodSummary, namespace string) string {
+	return renderPods(pods, fmt.Sprintf("No pods found in namespace %s.", namespace), func(pod api.PodSummary) string {
+		line := fmt.Sprintf("%s/%s on %s: ", pod.Namespace, pod.Name, pod.Node)
+		if pod.Reason != "" {
+			line += fmt.Sprintf("%s %s\n", pod.Reason, pod.Message)
+		}
+		return line
+	})
+}
+
+func renderPodsInNode(pods []api.PodSummary, nodeName string) string {
+	return renderPods(pods, fmt.Sprintf("No pods found on node %s.", nodeName), renderPodPhase)
+}
+
+func renderPodContainers(containers *api.PodContainers) string {
+	names := append(append(append([]string{}, containers.InitContainers...), containers.Containers...), containers.EphemeralContainers...)
+	if len(names) == 0 {
+		return "No containers found in pod."
+	}
+	return strings.Join(names, " ")
+}
+
+// renderConditionStatus renders a condition as its status and reason
+func renderConditionStatus(cond *api.Condition) string {
+	if cond == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (Reason: %s)", cond.Status, cond.Reason)
+}
+
+func renderOperatorStatuses(operators []api.OperatorStatus) string {
+	var b strings.Builder
+	for _, op := range operators {
+		fmt.Fprintf(&b, "Operator: %s\n  Available: %s\n  Progressing: %s\n  Degraded: %s\n\n",
+			op.Name, renderConditionStatus(op.Available), renderConditionStatus(op.Progressing), renderConditionStatus(op.Degraded))
+	}
+	return b.String()
+}
+
+func renderClusterVersion(cv *api.ClusterVersionSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cluster Version: %s\n", cv.Version)
+	fmt.Fprintf(&b, "Desired Image: %s\n", cv.Image)
+	fmt.Fprintf(&b, "Desired URL: %s\n", cv.URL)
+	fmt.Fprintf(&b, "Available Updates: %d\n", len(cv.AvailableUpdates))
+	for _, update := range cv.AvailableUpdates {
+		fmt.Fprintf(&b, "  - %s\n", update)
+	}
+
+	fmt.Fprintf(&b, "\nUpdate History:\n")
+	for _, hist := range cv.History {
+		fmt.Fprintf(&b, "  - Version: %s | State: %s | Verified: %t\n    Image: %s\n",
+			hist.Version, hist.State, hist.Verified, hist.Image)
+		completed := "N/A"
+		if hist.Completed != nil {
+			completed = hist.Completed.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(&b, "    Started: %s | Completed: %s\n", hist.Started.Format("2006-01-02 15:04:05"), completed)
+		if hist.AcceptedRisks != "" {
+			fmt.Fprintf(&b, "    Accepted Risks:\n    %s\n", indentMultiline(hist.AcceptedRisks, "    "))
+		}
+	}
+	return b.String()
+}
+
+// indentMultiline prefixes every line of s with indent
+func indentMultiline(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// orNotAvailable returns a placeholder if the string is empty
+func orNotAvailable(field string) string {
+	if field == "" {
+		return "<not available>"
+	}
+	return field
+}
+
+func renderNodeInfo(info *api.NodeInfo) string {
+	// If NodeInfo is empty (all fields are zero values), this indicates it wasn't populated.
+	if info.MachineID == "" && info.SystemUUID == "" && info.KernelVersion == "" && info.OSImage == "" {
+		return fmt.Sprintf("NodeInfo is not available for node %s", info.Name)
+	}
+	return fmt.Sprintf(
+		`Node Info for %s:
+  Architecture: %s
+  Boot ID: %s
+  Container Runtime Version: %s
+  Kernel Version: %s
+  KubeProxy Version: %s
+  Kubelet Version: %s
+  Machine ID: %s
+  Operating System: %s
+  OS Image: %s
+  System UUID: %s`,
+		info.Name,
+		orNotAvailable(info.Architecture),
+		orNotAvailable(info.BootID),
+		orNotAvailable(info.ContainerRuntimeVersion),
+		orNotAvailable(info.KernelVersion),
+		orNotAvailable(info.KubeProxyVersion),
+		orNotAvailable(info.KubeletVersion),
+		orNotAvailable(info.MachineID),
+		orNotAvailable(info.OperatingSystem),
+		orNotAvailable(info.OSImage),
+		orNotAvailable(info.SystemUUID),
+	)
+}
+
+func renderNodesInfo(infos []api.NodeInfo) string {
+	var b strings.Builder
+	for _, info := range infos {
+		fmt.Fprintf(&b, "Node: %s\n%s\n\n", info.Name, renderNodeInfo(&info))
+	}
+	return b.String()
+}
+
+// renderMap renders the entries of a map sorted by key, under a title
+func renderMap(kind, node string, m map[string]string) string {
+	if len(m) == 0 {
+		return fmt.Sprintf("No %s found for node %s", strings.ToLower(kind), node)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s for node %s:\n", kind, node)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  %s: %s\n", k, m[k])
+	}
+	return b.String()
+}
+
+func renderNodeLabels(labels *api.NodeLabels) string {
+	return renderMap("Labels", labels.Name, labels.Labels)
+}
+
+func renderNodesLabels(labels []api.NodeLabels) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(renderNodeLabels(&l))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderNodeAnnotations(annotations *api.NodeAnnotations) string {
+	return renderMap("Annotations", annotations.Name, annotations.Annotations)
+}
+
+func renderNodesAnnotations(annotations []api.NodeAnnotations) string {
+	var b strings.Builder
+	for _, a := range annotations {
+		b.WriteString(renderNodeAnnotations(&a))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderNodesConditions(nodes []api.NodeConditions) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		if len(node.Conditions) == 0 {
+			fmt.Fprintf(&b, "No conditions found for node %s\n", node.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "Conditions for node %s:\n", node.Name)
+		for _, cond := range node.Conditions {
+			fmt.Fprintf(&b, "  - Type: %s\n", cond.Type)
+			fmt.Fprintf(&b, "    Status: %s\n", cond.Status)
+			fmt.Fprintf(&b, "    Reason: %s\n", cond.Reason)
+			fmt.Fprintf(&b, "    Message: %s\n", cond.Message)
+			if cond.LastTransitionTime != nil {
+				fmt.Fprintf(&b, "    LastTransitionTime: %s\n", cond.LastTransitionTime.Format(time.RFC3339))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderText(s string) string {
+	return s
+}
+
+func renderList(items []string, sep, empty string) string {
+	if len(items) == 0 {
+		return empty
+	}
+	return strings.Join(items, sep)
+}
+
+func renderReleaseController(rc *api.ReleaseController) string {
+	return fmt.Sprintf("%s: %s (product %s, architecture %s)", rc.Name, rc.Host, rc.Product, rc.Architecture)
+}
+
+func renderTag(tag *api.Tag) string {
+	return fmt.Sprintf("%s, %s", tag.Name, tag.Phase)
+}
+
+func renderTags(tags []api.Tag) string {
+	var lines []string
+	for _, tag := range tags {
+		lines = append(lines, renderTag(&tag))
+	}
+	return renderList(lines, "\n", "No tags found")
+}
+
+func renderTagName(tag *api.Tag) string {
+	return tag.Name
+}
+
+// renderRetries describes the outcome of a job which was retried, empty if it was not
+func renderRetries(outcome api.JobOutcome, retries int) string {
+	if retries == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s, %d retries", strings.ReplaceAll(string(outcome), "_", " "), retries)
+}
+
+func renderFailedJobs(jobs []api.VerificationJob) string {
+	var lines []string
+	for _, job := range jobs {
+		if retries := renderRetries(job.Outcome, job.Retries); retries != "" {
+			lines = append(lines, fmt.Sprintf("%s (%s): %s", job.Name, retries, job.URL))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %s", job.Name, job.URL))
+		}
+	}
+	return renderList(lines, "\n", "No failed jobs found")
+}
+
+// jobDuration tells how long a job has been in its state
+func jobDuration(job api.PayloadJob) string {
+	if job.RunningFor != "" {
+		return fmt.Sprintf("running for %s", job.RunningFor)
+	}
+	if job.Since == nil {
+		return ""
+	}
+	return fmt.Sprintf("since %s", job.Since.UTC().Format(time.RFC3339))
+}
+
+func renderPayloadStatus(status *api.PayloadStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s): %s\n", status.Release, status.Phase, status.Verdict)
+	group := ""
+	for _, job := range status.Jobs {
+		if g := fmt.Sprintf("%s %s", job.State, job.Kind); g != group {
+			group = g
+			fmt.Fprintf(&b, "\n%s jobs:\n", group)
+		}
+		details := []string{}
+		if d := jobDuration(job); d != "" {
+			details = append(details, d)
+		}
+		if retries := renderRetries(job.Outcome, job.Retries); retries != "" {
+			details = append(details, retries)
+		}
+		fmt.Fprintf(&b, "- %s", job.Name)
+		if len(details) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(details, ", "))
+		}
+		if job.URL != "" {
+			fmt.Fprintf(&b, ": %s", job.URL)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// streamAcceptance summarizes the acceptance history of a stream in one line
+func streamAcceptance(health *api.StreamHealth) string {
+	summary := fmt.Sprintf("%d of the last %d payloads accepted, %d rejected (%.0f%% acceptance), longest rejection streak %d, currently %d rejected in a row",
+		health.Accepted, health.Payloads, health.Rejected, health.AcceptanceRate, health.LongestRejectionStreak, health.CurrentRejectionStreak)
+	switch {
+	case health.LastAccepted == "":
+		summary += ", none accepted"
+	case health.SinceLastAccepted != "":
+		summary += fmt.Sprintf(", last accepted %s %s ago", health.LastAccepted, health.SinceLastAccepted)
+	default:
+		summary += fmt.Sprintf(", last accepted %s", health.LastAccepted)
+	}
+	return summary
+}
+
+// renderUnavailable lists the payloads whose results could not be fetched, with why
+func renderUnavailable(payloads []api.UnavailablePayload) string {
+	var items []string
+	for _, payload := range payloads {
+		items = append(items, fmt.Sprintf("%s (%s)", payload.Name, payload.Error))
+	}
+	return strings.Join(items, ", ")
+}
+
+func renderStreamHealth(health *api.StreamHealth) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", health.Stream, streamAcceptance(health))
+	if len(health.Unavailable) > 0 {
+		fmt.Fprintf(&b, "Results unavailable for %s\n", renderUnavailable(health.Unavailable))
+	}
+	kind := api.JobKind("")
+	for _, job := range health.Jobs {
+		if job.Kind != kind {
+			kind = job.Kind
+			fmt.Fprintf(&b, "\n%s jobs:\n", kind)
+		}
+		fmt.Fprintf(&b, "- %s: %.0f%% (%d of %d passed)\n", job.Name, job.PassRate, job.Passed, job.Runs)
+	}
+	return b.String()
+}
+
+// matrixCell renders the state of a job in a payload, with its retries
+func matrixCell(cell api.MatrixCell) string {
+	if cell.Retries > 0 {
+		return fmt.Sprintf("%s (%d retries)", cell.State, cell.Retries)
+	}
+	return cell.State
+}
+
+func renderJobMatrix(matrix *api.JobMatrix) string {
+	if len(matrix.Payloads) == 0 {
+		return "No payloads found"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s payloads, newest first:\n", matrix.Stream)
+	for i, payload := range matrix.Payloads {
+		fmt.Fprintf(&b, "%d. %s (%s)", i+1, payload.Name, payload.Phase)
+		if !payload.Available {
+			b.WriteString(", results unavailable")
+			if payload.Error != "" {
+				fmt.Fprintf(&b, ": %s", payload.Error)
+			}
+		}
+		b.WriteString("\n")
+	}
+	kind := api.JobKind("")
+	for _, job := range matrix.Jobs {
+		if job.Kind != kind {
+			kind = job.Kind
+			fmt.Fprintf(&b, "\n%s jobs:\n", kind)
+		}
+		var cells []string
+		for _, cell := range job.Cells {
+			cells = append(cells, cmp.Or(matrixCell(cell), "-"))
+		}
+		fmt.Fprintf(&b, "- %s: %s", job.Name, strings.Join(cells, ", "))
+		if job.FailingSince != "" {
+			fmt.Fprintf(&b, "; failing since %s", job.FailingSince)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// bisectionSummary tells where the failure streak of a job started
+func bisectionSummary(b *api.JobBisection) string {
+	summary := fmt.Sprintf("%s %s job %s has failed in %d payloads in a row, first in %s", b.Stream, b.Kind, b.Job, b.Failures, b.FirstFailed)
+	if b.LastPassed == "" {
+		return summary + ", it did not pass in any of the payloads looked at, look further back to find the changes"
+	}
+	return summary + fmt.Sprintf(", it last passed in %s", b.LastPassed)
+}
+
+func renderJobBisection(b *api.JobBisection) string {
+	var s strings.Builder
+	s.WriteString(bisectionSummary(b) + "\n")
+	if b.FirstFailedURL != "" {
+		fmt.Fprintf(&s, "First failure: %s\n", b.FirstFailedURL)
+	}
+	if b.LastPassedURL != "" {
+		fmt.Fprintf(&s, "Last pass: %s\n", b.LastPassedURL)
+	}
+	if len(b.Unavailable) > 0 {
+		fmt.Fprintf(&s, "Results unavailable for %s\n", renderUnavailable(b.Unavailable))
+	}
+	if b.Suspects != nil {
+		s.WriteString("\nSuspects:\n" + renderReleaseComparison(b.Suspects))
+	}
+	return s.String()
+}
+
+func renderComponents(components []api.Component) string {
+	var lines []string
+	for _, component := range components {
+		lines = append(lines, fmt.Sprintf("%s: %s", component.Name, component.Version))
+	}
+	return renderList(lines, "\n", "No components found")
+}
+
+func renderIssues(issues []api.Issue) string {
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, fmt.Sprintf("%s: %s", issue.ID, issue.URL))
+	}
+	return renderList(lines, "\n", "No issues found in updated images commits")
+}
+
+func renderReleaseComparison(c *api.ReleaseComparison) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Changes from %s to %s\n", c.From, c.To)
+	b.WriteString("\nComponents:\n")
+	for _, component := range c.Components {
+		fmt.Fprintf(&b, "- %s: %s -> %s\n", component.Name, cmp.Or(component.From, "(new)"), component.To)
+	}
+	fmt.Fprintf(&b, "\nNew images: %s\n", renderList(c.NewImages, ", ", "none"))
+	fmt.Fprintf(&b, "Removed images: %s\n", renderList(c.RemovedImages, ", ", "none"))
+	var updated []string
+	for _, image := range c.UpdatedImages {
+		updated = append(updated, fmt.Sprintf("%s (%d commits)", image.Name, image.Commits))
+	}
+	fmt.Fprintf(&b, "Updated images: %s\n", renderList(updated, ", ", "none"))
+	fmt.Fprintf(&b, "\nCommits (%d):\n", len(c.Commits))
+	for _, commit := range c.Commits {
+		fmt.Fprintf(&b, "- [%s] %s", commit.Image, commit.Subject)
+		if len(commit.Issues) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(commit.Issues, ", "))
+		}
+		if commit.URL != "" {
+			fmt.Fprintf(&b, ": %s", commit.URL)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "\nIssues (%d):\n", len(c.Issues))
+	for _, issue := range c.Issues {
+		fmt.Fprintf(&b, "- %s: %s\n", issue.ID, issue.URL)
+	}
+	return b.String()
+}
+
+// renderUpgradeCounts renders the run counts of an upgrade edge on one line
+func renderUpgradeCounts(edge api.UpgradeEdge) string {
+	if edge.Total == 0 {
+		return fmt.Sprintf("%s -> %s: not tested", edge.From, edge.To)
+	}
+	return fmt.Sprintf("%s -> %s: %d succeeded, %d failed, %d total (%.0f%% success)", edge.From, edge.To, edge.Success, edge.Failure, edge.Total, edge.SuccessRate)
+}
+
+func renderReleaseUpgrades(upgrades *api.ReleaseUpgrades) string {
+	var b strings.Builder
+	for _, section := range []struct {
+		title string
+		edges []api.UpgradeEdge
+	}{
+		{"Upgrades to " + upgrades.Release, upgrades.To},
+		{"Upgrades from " + upgrades.Release, upgrades.From},
+	} {
+		fmt.Fprintf(&b, "%s:\n", section.title)
+		if len(section.edges) == 0 {
+			b.WriteString("none tested\n")
+		}
+		for _, edge := range section.edges {
+			fmt.Fprintf(&b, "- %s\n", renderUpgradeCounts(edge))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderUpgradeEdge(edge *api.UpgradeEdge) string {
+	if edge.Total == 0 {
+		return fmt.Sprintf("The upgrade from %s to %s has not been tested", edge.From, edge.To)
+	}
+	var b strings.Builder
+	b.WriteString(renderUpgradeCounts(*edge) + "\n")
+	for _, run := range edge.Runs {
+		fmt.Fprintf(&b, "- %s: %s\n", run.State, run.URL)
+	}
+	return b.String()
+}
+
+// renderTestLines renders the lines of the log reporting tests as they were printed
+func renderTestLines(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func renderFailingTests(results *api.TestResults) string {
+	if len(results.Lines) == 0 {
+		return fmt.Sprintf("No failing tests found for %s in job", results.Test)
+	}
+	return renderTestLines(results.Lines)
+}
+
+func renderFlakyTests(results *api.TestResults) string {
+	if len(results.Lines) == 0 {
+		return "No flaky tests found."
+	}
+	return renderTestLines(results.Lines)
+}
+
+func renderRiskAnalysis(risk *api.RiskAnalysis) string {
+	return string(risk.Data)
+}
+
+func renderSpyglassEvents(events []api.SpyglassEvent) string {
+	if len(events) == 0 {
+		return "No error or warning events found in spyglass data"
+	}
+	var b strings.Builder
+	for _, event := range events {
+		test := event.Test
+		if test == "" {
+			test = "Not a test"
+		}
+		fmt.Fprintf(&b, "Source: %s Type: %s Locator: 'test: %s' Reason: %s HumanMessage: %s From: %s To: %s\n",
+			event.Source,
+			event.Type,
+			test,
+			event.Reason,
+			event.Message,
+			event.From.Format(time.RFC3339),
+			event.To.Format(time.RFC3339),
+		)
+	}
+	return b.String()
+}
+
+func renderJobLog(log *api.JobLog) string {
+	return log.Log
+}
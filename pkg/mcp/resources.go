@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// resourceScheme is the URI scheme of the release controller resources:
+//
+//	releasecontroller://{controller}                 the release streams of a release controller
+//	releasecontroller://{controller}/{stream}        the tags of a release stream
+//	releasecontroller://{controller}/{stream}/{tag}  the verification results, upgrades and changelog of a release
+const resourceScheme = "releasecontroller://"
+
+// resourceTool returns the tool serving the same data as a resource, which the clients
+// must be allowed to call to read or subscribe to the resource
+func resourceTool(uri string) string {
+	switch strings.Count(strings.Trim(strings.TrimPrefix(uri, resourceScheme), "/"), "/") {
+	case 0:
+		return "list_release_streams"
+	case 1:
+		return "list_recent_tags"
+	default:
+		return "get_payload_status"
+	}
+}
+
+// releaseStreams is the content of a release controller resource
+type releaseStreams struct {
+	Controller string   `json:"controller"`
+	Streams    []string `json:"streams"`
+}
+
+// streamTags is the content of a release stream resource
+type streamTags struct {
+	Controller string    `json:"controller"`
+	Stream     string    `json:"stream"`
+	Tags       []api.Tag `json:"tags"`
+}
+
+// initResources registers a resource per configured release controller, and the
+// templates giving access to any release controller, stream and tag
+func (s *Server) initResources(ctx context.Context) {
+	for _, host := range s.releaseController.ListReleaseControllers(ctx) {
+		s.server.AddResource(mcp.NewResource(resourceScheme+host, host+" release streams",
+			mcp.WithResourceDescription(fmt.Sprintf("The release streams of the %s release controller", host)),
+			mcp.WithMIMEType("application/json"),
+		), authorizeResource(s.readReleaseStreams(host)))
+	}
+	s.server.AddResourceTemplate(mcp.NewResourceTemplate(resourceScheme+"{controller}", "Release streams",
+		mcp.WithTemplateDescription("The release streams of a release controller, given by name or host"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), server.ResourceTemplateHandlerFunc(authorizeResource(s.readReleaseStreams(""))))
+	s.server.AddResourceTemplate(mcp.NewResourceTemplate(resourceScheme+"{controller}/{stream}", "Release stream tags",
+		mcp.WithTemplateDescription("The tags of a release stream with their phase, pull spec and download URL"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), server.ResourceTemplateHandlerFunc(authorizeResource(s.readStreamTags)))
+	s.server.AddResourceTemplate(mcp.NewResourceTemplate(resourceScheme+"{controller}/{stream}/{tag}", "Release",
+		mcp.WithTemplateDescription("The verification job results, upgrades from and to, and changelog of a release"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), server.ResourceTemplateHandlerFunc(authorizeResource(s.readReleaseInfo)))
+}
+
+// readReleaseStreams reads the streams of a release controller, the one of the URI
+// template if host is empty
+func (s *Server) readReleaseStreams(host string) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		controller := host
+		if controller == "" {
+			controller = resourceArgument(request, "controller")
+		}
+		streams, err := s.releaseController.ListReleaseStreams(ctx, controller)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResource(request.Params.URI, releaseStreams{Controller: controller, Streams: streams})
+	}
+}
+
+func (s *Server) readStreamTags(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	controller, stream := resourceArgument(request, "controller"), resourceArgument(request, "stream")
+	tags, err := s.releaseController.ListReleaseTags(ctx, controller, stream)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResource(request.Params.URI, streamTags{Controller: controller, Stream: stream, Tags: tags})
+}
+
+func (s *Server) readReleaseInfo(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	info, err := s.releaseController.GetReleaseInfo(ctx, resourceArgument(request, "controller"), resourceArgument(request, "stream"), resourceArgument(request, "tag"))
+	if err != nil {
+		return nil, err
+	}
+	// The HTML changelog duplicates changeLogJson
+	release := *info
+	release.ChangeLog = nil
+	return jsonResource(request.Params.URI, release)
+}
+
+// resourceArgument returns a variable of the URI template matched by a request
+func resourceArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		if len(value) > 0 {
+			return value[0]
+		}
+	}
+	return ""
+}
+
+func jsonResource(uri string, content any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling resource %s: %w", uri, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasecontroller"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeReleaseController serves a single stream with two tags
+type fakeReleaseController struct {
+	releasecontroller.ReleaseController
+}
+
+func (fakeReleaseController) ListReleaseControllers(ctx context.Context) []string {
+	return []string{"amd64.ocp.releases.ci.openshift.org"}
+}
+
+func (fakeReleaseController) ListReleaseStreams(ctx context.Context, releasecontroller string) ([]string, error) {
+	return []string{"4.19.0-0.nightly"}, nil
+}
+
+func (fakeReleaseController) ListReleaseTags(ctx context.Context, releasecontroller, stream string) ([]api.Tag, error) {
+	return []api.Tag{
+		{Name: "4.19.0-0.nightly-2025-05-02-000000", Phase: "Rejected"},
+		{Name: "4.19.0-0.nightly-2025-05-01-000000", Phase: "Accepted"},
+	}, nil
+}
+
+func (fakeReleaseController) GetReleaseInfo(ctx context.Context, releasecontroller, stream, tag string) (*api.APIReleaseInfo, error) {
+	if tag != "4.19.0-0.nightly-2025-05-02-000000" {
+		return nil, fmt.Errorf("release %s not found", tag)
+	}
+	return &api.APIReleaseInfo{
+		Name:      tag,
+		Phase:     "Rejected",
+		Results:   &api.VerificationJobsSummary{BlockingJobs: api.VerificationStatusMap{"e2e-aws": {State: "Failed"}}},
+		ChangeLog: []byte("<html>"),
+	}, nil
+}
+
+// readResource reads a resource through the MCP server, returning its text or the error message
+func readResource(t *testing.T, s *server.MCPServer, uri string) string {
+	t.Helper()
+	request := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)
+	switch response := s.HandleMessage(context.Background(), json.RawMessage(request)).(type) {
+	case mcp.JSONRPCResponse:
+		return response.Result.(mcp.ReadResourceResult).Contents[0].(mcp.TextResourceContents).Text
+	case mcp.JSONRPCError:
+		return response.Error.Message
+	default:
+		t.Fatalf("unexpected response %#v", response)
+		return ""
+	}
+}
+
+func TestResources(t *testing.T) {
+	s := &Server{
+		server:            server.NewMCPServer("test", "0.0.0", server.WithResourceCapabilities(true, true)),
+		releaseController: fakeReleaseController{},
+	}
+	s.initResources(context.Background())
+
+	for uri, want := range map[string]string{
+		"releasecontroller://amd64.ocp.releases.ci.openshift.org":                                    `"streams": [`,
+		"releasecontroller://ocp":                                                                    `"controller": "ocp"`,
+		"releasecontroller://ocp/4.19.0-0.nightly":                                                   `"name": "4.19.0-0.nightly-2025-05-01-000000"`,
+		"releasecontroller://ocp/4.19.0-0.nightly/4.19.0-0.nightly-2025-05-02-000000":                `"blockingJobs"`,
+		"releasecontroller://ocp/4.19.0-0.nightly/4.19.0-0.nightly-2025-04-01-000000":                "release 4.19.0-0.nightly-2025-04-01-000000 not found",
+		"releasecontroller://ocp/4.19.0-0.nightly/4.19.0-0.nightly-2025-05-02-000000/extra/segments": "not found",
+	} {
+		if got := readResource(t, s.server, uri); !strings.Contains(got, want) {
+			t.Errorf("%s: expected %q in %s", uri, want, got)
+		}
+	}
+	if got := readResource(t, s.server, "releasecontroller://ocp/4.19.0-0.nightly/4.19.0-0.nightly-2025-05-02-000000"); strings.Contains(got, `"changeLog"`) {
+		t.Errorf("expected the HTML changelog to be left out: %s", got)
+	}
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SSEServer is the SSE transport of the MCP server. It passes the messages posted by
+// the clients to an interceptor before the MCP server, and only lets the principal
+// which opened a session post to it.
+type SSEServer struct {
+	*server.SSEServer
+	interceptor messageInterceptor
+
+	mu sync.Mutex
+	// owners are the principals which opened the sessions
+	owners map[string]string
+}
+
+func (s *SSEServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == s.CompleteSsePath() {
+		s.serveEvents(w, r)
+		return
+	}
+	sessionID := r.URL.Query().Get("sessionId")
+	if r.Method != http.MethodPost || r.URL.Path != s.CompleteMessagePath() || sessionID == "" {
+		s.SSEServer.ServeHTTP(w, r)
+		return
+	}
+	s.mu.Lock()
+	owner, ok := s.owners[sessionID]
+	s.mu.Unlock()
+	if ok && owner != sessionOwner(r.Context()) {
+		writeJSONRPCError(w, http.StatusForbidden, mcp.INVALID_REQUEST, "Session opened by another client")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		writeJSONRPCError(w, http.StatusRequestEntityTooLarge, mcp.INVALID_REQUEST, "Message too large")
+		return
+	}
+	notify := func(notification mcp.JSONRPCNotification) bool {
+		return s.SendEventToSession(sessionID, notification) == nil
+	}
+	response, ok := s.interceptor.intercept(r.Context(), sessionID, notify, body)
+	if !ok {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		s.SSEServer.ServeHTTP(w, r)
+		return
+	}
+	// Like the MCP server, answer on the event stream as well as in the response
+	if err := s.SendEventToSession(sessionID, response); err != nil {
+		s.interceptor.sessionClosed(sessionID)
+		writeJSONRPCError(w, http.StatusBadRequest, mcp.INVALID_PARAMS, "Invalid session ID")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// serveEvents serves an event stream, recording the principal which opened its session
+// from the endpoint event announcing the session ID
+func (s *SSEServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	recorder := &endpointRecorder{ResponseWriter: w, Flusher: flusher, owner: sessionOwner(r.Context()), server: s}
+	defer func() {
+		if recorder.sessionID != "" {
+			s.mu.Lock()
+			delete(s.owners, recorder.sessionID)
+			s.mu.Unlock()
+		}
+	}()
+	s.SSEServer.ServeHTTP(recorder, r)
+}
+
+// endpointRecorder records the owner of a session when the session ID is first written
+// to its event stream, before the client can post to it
+type endpointRecorder struct {
+	http.ResponseWriter
+	http.Flusher
+	owner     string
+	server    *SSEServer
+	sessionID string
+}
+
+func (e *endpointRecorder) Write(data []byte) (int, error) {
+	if e.sessionID == "" {
+		if _, query, ok := bytes.Cut(data, []byte("sessionId=")); ok {
+			if end := bytes.IndexAny(query, "&\r\n"); end >= 0 {
+				query = query[:end]
+			}
+			e.sessionID = string(query)
+			e.server.mu.Lock()
+			if e.server.owners == nil {
+				e.server.owners = map[string]string{}
+			}
+			e.server.owners[e.sessionID] = e.owner
+			e.server.mu.Unlock()
+		}
+	}
+	return e.ResponseWriter.Write(data)
+}
@@ -0,0 +1,303 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/httpclient"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasecontroller"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+	methodResourcesUpdated     = "notifications/resources/updated"
+
+	// DefaultPollInterval is how often the subscribed release streams are polled
+	DefaultPollInterval = 2 * time.Minute
+	// DefaultPollMaxBackoff is the longest delay between two polls of a stream failing to be fetched
+	DefaultPollMaxBackoff = 30 * time.Minute
+)
+
+// notifier sends a notification to a client session, it returns false once the session is gone
+type notifier func(mcp.JSONRPCNotification) bool
+
+// messageInterceptor handles some messages of the clients in place of the MCP server
+type messageInterceptor interface {
+	// intercept returns the response to a message, or false if the MCP server must handle it
+	intercept(ctx context.Context, sessionID string, notify notifier, message json.RawMessage) (mcp.JSONRPCMessage, bool)
+	// sessionClosed forgets the state kept for a session
+	sessionClosed(sessionID string)
+}
+
+// subscriptions polls the release streams whose resources are subscribed to, and
+// notifies the subscribers when a tag appears or changes phase. The MCP server
+// declares the subscribe capability but does not handle the subscribe requests, so
+// the transports pass them to subscriptions with intercept.
+type subscriptions struct {
+	releaseController releasecontroller.ReleaseController
+	interval          time.Duration
+	maxBackoff        time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]notifier
+	streams  map[streamKey]*streamPoller
+}
+
+// streamKey identifies a release stream, by the controller name or host used in the URIs
+type streamKey struct {
+	controller, stream string
+}
+
+// streamPoller polls a release stream while its resources are subscribed to
+type streamPoller struct {
+	// subscribers are the sessions subscribed to each URI of the stream: the stream itself and its tags
+	subscribers map[string]map[string]bool
+	cancel      context.CancelFunc
+}
+
+func newSubscriptions(releaseController releasecontroller.ReleaseController, interval, maxBackoff time.Duration) *subscriptions {
+	return &subscriptions{
+		releaseController: releaseController,
+		interval:          interval,
+		maxBackoff:        max(maxBackoff, interval),
+		sessions:          map[string]notifier{},
+		streams:           map[streamKey]*streamPoller{},
+	}
+}
+
+func (sub *subscriptions) intercept(ctx context.Context, sessionID string, notify notifier, message json.RawMessage) (mcp.JSONRPCMessage, bool) {
+	var request struct {
+		ID     mcp.RequestId `json:"id"`
+		Method string        `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || request.ID == nil {
+		return nil, false
+	}
+	var err error
+	switch request.Method {
+	case methodResourcesSubscribe:
+		if err = allowed(ctx, "subscribe to", request.Params.URI, "resource"); err == nil {
+			err = sub.subscribe(sessionID, notify, request.Params.URI)
+		}
+	case methodResourcesUnsubscribe:
+		sub.unsubscribe(sessionID, request.Params.URI)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil), true
+	}
+	return mcp.NewJSONRPCResponse(request.ID, mcp.Result{}), true
+}
+
+// parseStreamURI returns the stream of a stream or release resource URI
+func parseStreamURI(uri string) (streamKey, error) {
+	parts := strings.Split(strings.TrimPrefix(uri, resourceScheme), "/")
+	if !strings.HasPrefix(uri, resourceScheme) || len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return streamKey{}, fmt.Errorf("cannot subscribe to %s, only %s{controller}/{stream} and %s{controller}/{stream}/{tag} resources can be subscribed to", uri, resourceScheme, resourceScheme)
+	}
+	return streamKey{controller: parts[0], stream: parts[1]}, nil
+}
+
+// subscribe subscribes a session to a resource, starting to poll its stream if needed
+func (sub *subscriptions) subscribe(sessionID string, notify notifier, uri string) error {
+	key, err := parseStreamURI(uri)
+	if err != nil {
+		return err
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.sessions[sessionID] = notify
+	poller, ok := sub.streams[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		poller = &streamPoller{subscribers: map[string]map[string]bool{}, cancel: cancel}
+		sub.streams[key] = poller
+		go sub.poll(ctx, key)
+	}
+	if poller.subscribers[uri] == nil {
+		poller.subscribers[uri] = map[string]bool{}
+	}
+	poller.subscribers[uri][sessionID] = true
+	return nil
+}
+
+// unsubscribe unsubscribes a session from a resource, and stops polling its stream
+// once nobody is subscribed to it
+func (sub *subscriptions) unsubscribe(sessionID, uri string) {
+	key, err := parseStreamURI(uri)
+	if err != nil {
+		return
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if poller, ok := sub.streams[key]; ok {
+		sub.removeSubscriber(key, poller, uri, sessionID)
+	}
+}
+
+func (sub *subscriptions) sessionClosed(sessionID string) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	delete(sub.sessions, sessionID)
+	for key, poller := range sub.streams {
+		for uri := range poller.subscribers {
+			sub.removeSubscriber(key, poller, uri, sessionID)
+		}
+	}
+}
+
+// removeSubscriber must be called with mu held
+func (sub *subscriptions) removeSubscriber(key streamKey, poller *streamPoller, uri, sessionID string) {
+	delete(poller.subscribers[uri], sessionID)
+	if len(poller.subscribers[uri]) == 0 {
+		delete(poller.subscribers, uri)
+	}
+	if len(poller.subscribers) == 0 {
+		poller.cancel()
+		delete(sub.streams, key)
+	}
+}
+
+// poll fetches the tags of a stream until the context is done. The first fetch gives
+// the known phase of the tags, later ones are compared to it. The tags are read from
+// the release controller rather than the cache, whose TTL may be longer than the interval.
+func (sub *subscriptions) poll(ctx context.Context, key streamKey) {
+	var known map[string]string
+	failures := 0
+	for {
+		tags, err := sub.releaseController.ListReleaseTags(httpclient.BypassCache(ctx), key.controller, key.stream)
+		if err != nil {
+			failures++
+		} else {
+			failures = 0
+			phases := make(map[string]string, len(tags))
+			var changed []string
+			for _, tag := range tags {
+				phases[tag.Name] = tag.Phase
+				if phase, ok := known[tag.Name]; known != nil && (!ok || phase != tag.Phase) {
+					changed = append(changed, tag.Name)
+				}
+			}
+			known = phases
+			if len(changed) > 0 {
+				sub.notify(key, changed)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sub.pollDelay(failures)):
+		}
+	}
+}
+
+// pollDelay returns the delay before the next poll, doubling with each consecutive failure
+func (sub *subscriptions) pollDelay(failures int) time.Duration {
+	delay := sub.interval
+	for i := 0; i < failures && delay < sub.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, sub.maxBackoff)
+}
+
+// notify notifies the subscribers of the stream and of the changed tags. Sessions
+// which are gone are unsubscribed.
+func (sub *subscriptions) notify(key streamKey, changedTags []string) {
+	streamURI := fmt.Sprintf("%s%s/%s", resourceScheme, key.controller, key.stream)
+	uris := []string{streamURI}
+	for _, tag := range changedTags {
+		uris = append(uris, streamURI+"/"+tag)
+	}
+	type delivery struct {
+		sessionID, uri string
+		notify         notifier
+	}
+	var deliveries []delivery
+	sub.mu.Lock()
+	if poller, ok := sub.streams[key]; ok {
+		for _, uri := range uris {
+			for sessionID := range poller.subscribers[uri] {
+				deliveries = append(deliveries, delivery{sessionID, uri, sub.sessions[sessionID]})
+			}
+		}
+	}
+	sub.mu.Unlock()
+	for _, d := range deliveries {
+		notification := mcp.JSONRPCNotification{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Notification: mcp.Notification{
+				Method: methodResourcesUpdated,
+				Params: mcp.NotificationParams{AdditionalFields: map[string]any{"uri": d.uri}},
+			},
+		}
+		if d.notify == nil || !d.notify(notification) {
+			sub.sessionClosed(d.sessionID)
+		}
+	}
+}
+
+// interceptLines passes the messages read from in, one per line, to the interceptor
+// and returns a reader of the messages it did not handle. The responses of the
+// interceptor and the notifications are written to out.
+func interceptLines(interceptor messageInterceptor, sessionID string, in io.Reader, out io.Writer) io.Reader {
+	pr, pw := io.Pipe()
+	writeMessage := func(message any) bool {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return false
+		}
+		_, err = out.Write(append(data, '\n'))
+		return err == nil
+	}
+	notify := func(notification mcp.JSONRPCNotification) bool {
+		return writeMessage(notification)
+	}
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response, ok := interceptor.intercept(context.Background(), sessionID, notify, line); ok {
+					writeMessage(response)
+				} else if _, werr := pw.Write(line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				interceptor.sessionClosed(sessionID)
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr
+}
+
+// lockedWriter serializes the writes of the messages sent on stdout
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
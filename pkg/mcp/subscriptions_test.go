@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// changingReleaseController serves a stream whose tags can be changed by the test
+type changingReleaseController struct {
+	fakeReleaseController
+	mu   sync.Mutex
+	tags []api.Tag
+}
+
+func (c *changingReleaseController) ListReleaseTags(ctx context.Context, releasecontroller, stream string) ([]api.Tag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]api.Tag(nil), c.tags...), nil
+}
+
+func (c *changingReleaseController) setTags(tags ...api.Tag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tags = tags
+}
+
+func subscribeMessage(method, uri string) json.RawMessage {
+	return json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":{"uri":"` + uri + `"}}`)
+}
+
+// nextUpdate returns the URI of the next resource updated notification
+func nextUpdate(t *testing.T, notifications <-chan mcp.JSONRPCNotification) string {
+	t.Helper()
+	select {
+	case n := <-notifications:
+		if n.Method != methodResourcesUpdated {
+			t.Fatalf("unexpected notification %s", n.Method)
+		}
+		return n.Params.AdditionalFields["uri"].(string)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification received")
+		return ""
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	rc := &changingReleaseController{}
+	rc.setTags(api.Tag{Name: "4.19.0-0.nightly-2025-05-01-000000", Phase: "Ready"})
+	sub := newSubscriptions(rc, 10*time.Millisecond, time.Second)
+	notifications := make(chan mcp.JSONRPCNotification, 10)
+	notify := func(n mcp.JSONRPCNotification) bool {
+		notifications <- n
+		return true
+	}
+
+	const streamURI = "releasecontroller://ocp/4.19.0-0.nightly"
+	const tagURI = streamURI + "/4.19.0-0.nightly-2025-05-01-000000"
+	for _, uri := range []string{streamURI, tagURI} {
+		response, ok := sub.intercept(context.Background(), "session", notify, subscribeMessage(methodResourcesSubscribe, uri))
+		if _, isResponse := response.(mcp.JSONRPCResponse); !ok || !isResponse {
+			t.Fatalf("unexpected response to subscribe: %#v", response)
+		}
+	}
+	if response, ok := sub.intercept(context.Background(), "session", notify, subscribeMessage(methodResourcesSubscribe, "releasecontroller://ocp")); !ok {
+		t.Fatal("subscribe not intercepted")
+	} else if _, isError := response.(mcp.JSONRPCError); !isError {
+		t.Errorf("expected subscribing to a release controller to fail, got %#v", response)
+	}
+	if _, ok := sub.intercept(context.Background(), "session", notify, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)); ok {
+		t.Errorf("expected other messages to be left to the MCP server")
+	}
+
+	// Let the poller learn the current tags, then change the phase of the tag
+	time.Sleep(50 * time.Millisecond)
+	rc.setTags(api.Tag{Name: "4.19.0-0.nightly-2025-05-01-000000", Phase: "Accepted"})
+	got := map[string]bool{nextUpdate(t, notifications): true, nextUpdate(t, notifications): true}
+	if !got[streamURI] || !got[tagURI] {
+		t.Errorf("expected the stream and tag to be updated, got %v", got)
+	}
+
+	// A new tag only updates the stream
+	sub.intercept(context.Background(), "session", notify, subscribeMessage(methodResourcesUnsubscribe, tagURI))
+	rc.setTags(
+		api.Tag{Name: "4.19.0-0.nightly-2025-05-02-000000", Phase: "Ready"},
+		api.Tag{Name: "4.19.0-0.nightly-2025-05-01-000000", Phase: "Accepted"},
+	)
+	if uri := nextUpdate(t, notifications); uri != streamURI {
+		t.Errorf("expected the stream to be updated, got %s", uri)
+	}
+
+	sub.sessionClosed("session")
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if len(sub.streams) != 0 || len(sub.sessions) != 0 {
+		t.Errorf("expected polling to stop once the session is closed")
+	}
+}
+
+func TestPollDelay(t *testing.T) {
+	sub := newSubscriptions(nil, time.Minute, 5*time.Minute)
+	for failures, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		if got := sub.pollDelay(failures); got != want {
+			t.Errorf("%d failures: expected %s, got %s", failures, want, got)
+		}
+	}
+}
+
+func TestInterceptLines(t *testing.T) {
+	sub := newSubscriptions(&changingReleaseController{}, time.Hour, time.Hour)
+	in := strings.NewReader(string(subscribeMessage(methodResourcesSubscribe, "releasecontroller://ocp/4.19.0-0.nightly")) + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n")
+	var out bytes.Buffer
+	forwarded, err := io.ReadAll(interceptLines(sub, "stdio", in, &lockedWriter{w: &out}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(forwarded) != `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n" {
+		t.Errorf("unexpected forwarded messages %q", forwarded)
+	}
+	if out.String() != `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n" {
+		t.Errorf("unexpected intercepted responses %q", out.String())
+	}
+	if len(sub.streams) != 0 {
+		t.Errorf("expected the subscriptions to end with stdin")
+	}
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolsets groups the tools by what they work on, so that a deployment can only
+// register the ones it needs. Every tool belongs to exactly one toolset.
+var toolsets = map[string][]string{
+	"release": {
+		"list_release_controllers",
+		"resolve_release_controller",
+		"list_release_streams",
+		"latest_release",
+		"latest_accepted_release",
+		"latest_rejected_release",
+		"list_recent_tags",
+		"list_failed_jobs_in_release",
+		"get_payload_status",
+		"get_stream_health",
+		"get_job_matrix",
+		"bisect_job_failure",
+		"list_components_in_release",
+	},
+	"upgrade": {
+		"list_upgrades_for_release",
+		"get_upgrade_edge",
+	},
+	"changelog": {
+		"compare_releases",
+		"list_features_from_updated_images_commits",
+		"list_bugs_from_updated_images_commits",
+		"list_cves_from_updated_images_commits",
+	},
+	"prowjob": {
+		"list_test_failures_for_release",
+		"get_flaky_tests_for_release",
+		"get_risk_analysis_data",
+		"get_top_level_build_log",
+		"analyze_job_failures_for_release",
+	},
+	"spyglass": {
+		"get_spyglass_data_relevant_to_test_failure",
+	},
+	"cluster": {
+		"get_pods_in_state",
+		"get_pods_in_namespace",
+		"get_pods_in_node",
+		"get_containers_in_pod",
+		"get_container_logs",
+		"get_cluster_operator_status_summary",
+		"get_cluster_version_summary",
+	},
+	"nodes": {
+		"get_nodes_info",
+		"get_node_info_by_name",
+		"get_node_labels_by_name",
+		"get_node_annotations_by_name",
+		"get_nodes_labels",
+		"get_nodes_annotations",
+		"get_nodes_conditions",
+	},
+	"cache": {
+		"get_cache_stats",
+	},
+	"export": {
+		"export_release_data",
+	},
+}
+
+// writingTools are the tools with side effects outside of their result, such as
+// writing files, which are not registered in read-only mode
+var writingTools = []string{
+	// writes JSON snapshots in the export directory
+	"export_release_data",
+}
+
+// Toolsets returns the names of the toolsets, sorted
+func Toolsets() []string {
+	return slices.Sorted(maps.Keys(toolsets))
+}
+
+// WithToolsets only registers the tools of the given toolsets, all of them if empty
+func WithToolsets(names ...string) Option {
+	return func(s *Server) {
+		s.toolsets = names
+	}
+}
+
+// WithDisabledTools does not register the given tools
+func WithDisabledTools(names ...string) Option {
+	return func(s *Server) {
+		s.disabledTools = names
+	}
+}
+
+// WithReadOnly only registers the tools without side effects
+func WithReadOnly(readOnly bool) Option {
+	return func(s *Server) {
+		s.readOnly = readOnly
+	}
+}
+
+// selectTools returns the tools enabled by the toolsets, disabled tools and read-only
+// options of the server
+func (s *Server) selectTools(tools []server.ServerTool) ([]server.ServerTool, error) {
+	enabled := map[string]bool{}
+	for _, name := range s.toolsets {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		names, ok := toolsets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown toolset %s, expected one of %s", name, strings.Join(Toolsets(), ", "))
+		}
+		for _, tool := range names {
+			enabled[tool] = true
+		}
+	}
+	disabled := map[string]bool{}
+	for _, name := range s.disabledTools {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(tools, func(t server.ServerTool) bool { return t.Tool.Name == name }) {
+			return nil, fmt.Errorf("cannot disable unknown tool %s", name)
+		}
+		disabled[name] = true
+	}
+	if s.readOnly {
+		for _, name := range writingTools {
+			disabled[name] = true
+		}
+	}
+	var selected []server.ServerTool
+	for _, tool := range tools {
+		if (len(enabled) == 0 || enabled[tool.Tool.Name]) && !disabled[tool.Tool.Name] {
+			selected = append(selected, tool)
+		}
+	}
+	return selected, nil
+}
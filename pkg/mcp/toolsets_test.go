@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func allTools() []server.ServerTool {
+	s := &Server{}
+	return s.tools()
+}
+
+func toolNames(tools []server.ServerTool) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	return names
+}
+
+func TestEveryToolHasOneToolset(t *testing.T) {
+	count := map[string]int{}
+	for _, names := range toolsets {
+		for _, name := range names {
+			count[name]++
+		}
+	}
+	for _, name := range toolNames(allTools()) {
+		if count[name] != 1 {
+			t.Errorf("tool %s is in %d toolsets", name, count[name])
+		}
+		delete(count, name)
+	}
+	for name := range count {
+		t.Errorf("toolset tool %s does not exist", name)
+	}
+}
+
+func TestSelectTools(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		server Server
+		want   []string
+		err    bool
+	}{
+		{name: "all", server: Server{}, want: toolNames(allTools())},
+		{name: "toolsets", server: Server{toolsets: []string{"spyglass", "cache"}}, want: []string{"get_spyglass_data_relevant_to_test_failure", "get_cache_stats"}},
+		{name: "disabled", server: Server{toolsets: []string{"spyglass", "cache"}, disabledTools: []string{"get_cache_stats"}}, want: []string{"get_spyglass_data_relevant_to_test_failure"}},
+		{name: "read-only", server: Server{toolsets: []string{"cache", "export"}, readOnly: true}, want: []string{"get_cache_stats"}},
+		{name: "unknown toolset", server: Server{toolsets: []string{"builds"}}, err: true},
+		{name: "unknown tool", server: Server{disabledTools: []string{"get_build"}}, err: true},
+	} {
+		tools, err := tc.server.selectTools(allTools())
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+			continue
+		}
+		if got := toolNames(tools); !tc.err && !slices.Equal(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+// templates are the prompt templates shipped with the server. A template starts with
+// a YAML front matter describing the prompt, templates whose name starts with _ only
+// define blocks shared by the others.
+//
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+const templateExt = ".tmpl"
+
+// Argument is an argument of a prompt, available as .<name> in its template
+type Argument struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	Required    bool   `mapstructure:"required"`
+}
+
+// Prompt is a prompt template offered by the server
+type Prompt struct {
+	Name        string     `mapstructure:"-"`
+	Description string     `mapstructure:"description"`
+	Version     int        `mapstructure:"version"`
+	Arguments   []Argument `mapstructure:"arguments"`
+	// Source is the file the template was loaded from, empty for an embedded template
+	Source string `mapstructure:"-"`
+}
+
+// Set is a set of prompts with their templates
+type Set struct {
+	prompts []*Prompt
+	// shared holds the blocks shared by the prompts
+	shared *template.Template
+	// templates holds the template of each prompt, parsed with its own copy of the
+	// shared blocks so that the blocks it overrides only apply to it
+	templates map[string]*template.Template
+}
+
+// Load loads the embedded prompt templates, then the templates found in dir if it is
+// not empty. A template in dir replaces the embedded template with the same file name.
+func Load(dir string) (*Set, error) {
+	sources := map[string]string{}
+	files := map[string][]byte{}
+	if err := readTemplates(templates, "templates", "", sources, files); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := readTemplates(os.DirFS(dir), ".", dir, sources, files); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	// Parse the shared blocks first so that the prompts can override them
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := strings.HasPrefix(names[i], "_"), strings.HasPrefix(names[j], "_")
+		if pi != pj {
+			return pi
+		}
+		return names[i] < names[j]
+	})
+
+	s := &Set{shared: template.New("").Option("missingkey=zero"), templates: map[string]*template.Template{}}
+	for _, name := range names {
+		body := string(files[name])
+		t := s.shared
+		if !strings.HasPrefix(name, "_") {
+			prompt, rest, err := parseFrontMatter(body)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing prompt %s: %w", templateSource(name, sources[name]), err)
+			}
+			prompt.Name, prompt.Source = name, sources[name]
+			s.prompts = append(s.prompts, prompt)
+			body = rest
+			if t, err = s.shared.Clone(); err != nil {
+				return nil, err
+			}
+			s.templates[name] = t
+		}
+		if _, err := t.New(name).Parse(body); err != nil {
+			return nil, fmt.Errorf("error parsing prompt template %s: %w", templateSource(name, sources[name]), err)
+		}
+	}
+	return s, nil
+}
+
+// readTemplates reads the templates of a directory, recording where each one comes from
+func readTemplates(fsys fs.FS, dir, source string, sources map[string]string, files map[string][]byte) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("error reading prompt templates: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != templateExt {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading prompt template %s: %w", entry.Name(), err)
+		}
+		name := strings.TrimSuffix(entry.Name(), templateExt)
+		files[name] = data
+		sources[name] = source
+	}
+	return nil
+}
+
+func templateSource(name, dir string) string {
+	if dir == "" {
+		return name
+	}
+	return path.Join(dir, name+templateExt)
+}
+
+// parseFrontMatter splits a template between the YAML front matter describing the
+// prompt and the template body
+func parseFrontMatter(body string) (*Prompt, string, error) {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	if !strings.HasPrefix(body, "---\n") {
+		return nil, "", fmt.Errorf("missing front matter, the template must start with ---")
+	}
+	frontMatter, rest, found := strings.Cut(body[len("---\n"):], "\n---\n")
+	if !found {
+		return nil, "", fmt.Errorf("front matter is not closed with ---")
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(frontMatter)); err != nil {
+		return nil, "", err
+	}
+	prompt := &Prompt{}
+	if err := v.Unmarshal(prompt); err != nil {
+		return nil, "", err
+	}
+	for i, arg := range prompt.Arguments {
+		if arg.Name == "" {
+			return nil, "", fmt.Errorf("argument %d has no name", i)
+		}
+	}
+	return prompt, rest, nil
+}
+
+// Prompts returns the prompts of the set, sorted by name
+func (s *Set) Prompts() []*Prompt {
+	return s.prompts
+}
+
+// Render renders a prompt with its arguments
+func (s *Set) Render(name string, args map[string]string) (string, error) {
+	i := slices.IndexFunc(s.prompts, func(p *Prompt) bool { return p.Name == name })
+	if i < 0 {
+		return "", fmt.Errorf("unknown prompt %s", name)
+	}
+	data := map[string]string{}
+	for _, arg := range s.prompts[i].Arguments {
+		value := strings.TrimSpace(args[arg.Name])
+		if value == "" && arg.Required {
+			return "", fmt.Errorf("missing required argument %s of prompt %s", arg.Name, name)
+		}
+		data[arg.Name] = value
+	}
+	var b bytes.Buffer
+	if err := s.templates[name].ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("error rendering prompt %s: %w", name, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,129 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedPrompts(t *testing.T) {
+	set, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range set.Prompts() {
+		names = append(names, p.Name)
+		if p.Description == "" || p.Version < 1 || p.Source != "" {
+			t.Errorf("unexpected prompt %+v", p)
+		}
+	}
+	if got := strings.Join(names, ","); got != "analyze_job,releasecontroller_instructions,summarize_release_changes,triage_rejected_payload" {
+		t.Errorf("unexpected prompts %s", got)
+	}
+
+	text, err := set.Render("triage_rejected_payload", map[string]string{
+		"releasecontroller": "amd64.ocp.releases.ci.openshift.org",
+		"stream":            "4.19.0-0.nightly",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"latest_rejected_release tool with releasecontroller amd64.ocp.releases.ci.openshift.org and stream 4.19.0-0.nightly", "GENERAL FLOW FOR FAILURE ANALYSIS"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "no value") {
+		t.Errorf("unset argument rendered in:\n%s", text)
+	}
+
+	if _, err := set.Render("analyze_job", nil); err == nil || !strings.Contains(err.Error(), "prowurl") {
+		t.Errorf("expected missing prowurl to fail, got %v", err)
+	}
+	if _, err := set.Render("unknown", nil); err == nil {
+		t.Errorf("expected an unknown prompt to fail")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"_rules.tmpl": `{{define "general_rules"}}Be brief.{{end}}{{define "failure_analysis_flow"}}{{end}}{{define "spyglass_rules"}}{{end}}`,
+		"analyze_job.tmpl": `---
+description: Analyze a job, our way
+version: 7
+arguments:
+  - name: prowurl
+    required: true
+---
+Look at {{.prowurl}}. {{template "general_rules"}}`,
+		"flaky_tests.tmpl": `---
+description: Find flaky tests
+version: 1
+---
+Find flaky tests.`,
+		"notes.txt": "not a template",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(set.Prompts()); n != 5 {
+		t.Errorf("expected 5 prompts, got %d", n)
+	}
+	text, err := set.Render("analyze_job", map[string]string{"prowurl": "https://prow/job/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Look at https://prow/job/1. Be brief." {
+		t.Errorf("unexpected overridden prompt %q", text)
+	}
+	for _, p := range set.Prompts() {
+		if p.Name == "analyze_job" && (p.Version != 7 || p.Source != dir) {
+			t.Errorf("unexpected overridden prompt %+v", p)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte("no front matter"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Errorf("expected a template without front matter to fail")
+	}
+}
+
+func TestBlockOverrideOnlyAppliesToItsPrompt(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"_rules.tmpl": `{{define "general_rules"}}Be brief.{{end}}`,
+		"analyze_job.tmpl": `---
+description: Analyze a job
+---
+{{define "general_rules"}}Be thorough.{{end}}Analyze. {{template "general_rules"}}`,
+		"triage.tmpl": `---
+description: Triage a payload
+---
+Triage. {{template "general_rules"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[string]string{"analyze_job": "Analyze. Be thorough.", "triage": "Triage. Be brief."} {
+		if text, err := set.Render(name, nil); err != nil || text != expected {
+			t.Errorf("%s: expected %q, got %q %v", name, expected, text, err)
+		}
+	}
+}
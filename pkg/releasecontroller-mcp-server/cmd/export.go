@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/export"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasecontroller"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export --releasecontroller <controller> --stream <stream> [--tag <tag>] [--output-dir <dir>]",
+	Short: "Save the tags of a release stream or a release as JSON, for archiving and offline analysis",
+	Long: `
+Save the tags of a release stream, or the full release information of a tag, as JSON
+files in the format served by the release controller API:
+
+  <output-dir>/<controller>/<stream>/tags.json
+  <output-dir>/<controller>/<stream>/<tag>.json
+
+  # save the tags of the 4.19.0-0.nightly stream of the OCP release controller
+  releasecontroller-mcp-server export --releasecontroller ocp --stream 4.19.0-0.nightly
+
+  # save the verification results, upgrades and changelog of a release to ./exports
+  releasecontroller-mcp-server export --releasecontroller okd --stream 4.20.0-0.okd-scos \
+    --tag 4.20.0-0.okd-scos-2025-05-02-123456 --output-dir ./exports`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := setup()
+		if err != nil {
+			return err
+		}
+		controller, _ := cmd.Flags().GetString("releasecontroller")
+		stream, _ := cmd.Flags().GetString("stream")
+		tag, _ := cmd.Flags().GetString("tag")
+		dir, _ := cmd.Flags().GetString("output-dir")
+		path, err := export.Export(cmd.Context(), releasecontroller.NewReleaseController(cfg), controller, stream, tag, dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().String("releasecontroller", "", "Name, alias or host of the release controller")
+	exportCmd.Flags().String("stream", "", "Release stream to export")
+	exportCmd.Flags().String("tag", "", "Release to export, the tags of the stream are exported if empty")
+	exportCmd.Flags().String("output-dir", ".", "Directory to write the JSON files to")
+	_ = exportCmd.MarkFlagRequired("releasecontroller")
+	_ = exportCmd.MarkFlagRequired("stream")
+	rootCmd.AddCommand(exportCmd)
+}
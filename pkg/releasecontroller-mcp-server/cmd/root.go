@@ -1,14 +1,26 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/auth"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/cache"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/httpclient"
 	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/mcp"
 	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/version"
-	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"golang.org/x/net/context"
 )
 
 var rootCmd = &cobra.Command{
@@ -29,41 +41,268 @@ releasecontroller Model Context Protocol (MCP) server
   # start a SSE server on port 8080
   releasecontroller-mcp-server --sse-port 8080
 
+  # start a streamable HTTP server on port 8080, serving the /mcp endpoint
+  releasecontroller-mcp-server --http-port 8080
+
+  # start both a SSE server on port 8080 and a streamable HTTP server on port 8081
+  releasecontroller-mcp-server --sse-port 8080 --http-port 8081
+
   # start a SSE server on port 8443 with a public HTTPS host of example.com
   releasecontroller-mcp-server --sse-port 8443 --sse-base-url https://example.com:8443
 
+  # start a SSE server on port 8443 over HTTPS, only accepting the tokens listed in tokens.yaml
+  releasecontroller-mcp-server --sse-port 8443 --tls-cert-file tls.crt --tls-key-file tls.key --auth-token-file tokens.yaml
+
+  # start a streamable HTTP server on port 8443 only accepting clients with a certificate signed by ca.crt
+  releasecontroller-mcp-server --http-port 8443 --tls-cert-file tls.crt --tls-key-file tls.key --tls-client-ca-file ca.crt
+
+  # start a STDIO server talking to the release controllers and Prow instances listed in config.yaml
+  releasecontroller-mcp-server --config config.yaml
+
+  # start a STDIO server caching responses in /tmp/rc-cache, starting from an empty cache
+  releasecontroller-mcp-server --cache-dir /tmp/rc-cache --clear-cache
+
+  # start a STDIO server returning tool results in pages of at most 64 KiB
+  releasecontroller-mcp-server --max-response-size 64
+
+  # start a STDIO server with the prompt templates in ./prompts replacing the built-in ones with the same name
+  releasecontroller-mcp-server --prompts-dir ./prompts
+
+  # start a STDIO server with only the release and prow job tools, without get_risk_analysis_data
+  releasecontroller-mcp-server --toolsets release,prowjob --disable-tools get_risk_analysis_data
+
+  # start a STDIO server letting the export_release_data tool write to /var/lib/exports
+  releasecontroller-mcp-server --export-dir /var/lib/exports
+
+  # save the tags of the 4.19.0-0.nightly stream to ./exports
+  releasecontroller-mcp-server export --releasecontroller ocp --stream 4.19.0-0.nightly --output-dir ./exports
+
   # TODO: add more examples`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if viper.GetBool("version") {
 			fmt.Println(version.Version)
 			return
 		}
-		mcpServer, err := mcp.NewSever()
+		cfg, err := setup()
 		if err != nil {
 			panic(err)
 		}
-
-		var sseServer *server.SSEServer
-		if ssePort := viper.GetInt("sse-port"); ssePort > 0 {
-			sseServer = mcpServer.ServeSse(viper.GetString("sse-base-url"))
-			if err := sseServer.Start(fmt.Sprintf(":%d", ssePort)); err != nil {
-				panic(err)
-			}
+		mcpServer, err := mcp.NewSever(cfg,
+			mcp.WithMaxResponseSize(viper.GetInt("max-response-size")*1024),
+			mcp.WithPromptsDir(viper.GetString("prompts-dir")),
+			mcp.WithPollInterval(viper.GetDuration("poll-interval")),
+			mcp.WithPollMaxBackoff(viper.GetDuration("poll-max-backoff")),
+			mcp.WithToolsets(viper.GetStringSlice("toolsets")...),
+			mcp.WithDisabledTools(viper.GetStringSlice("disable-tools")...),
+			mcp.WithReadOnly(viper.GetBool("read-only")),
+			mcp.WithExportDir(viper.GetString("export-dir")),
+		)
+		if err != nil {
+			panic(err)
 		}
-		if err := mcpServer.ServeStdio(); err != nil && !errors.Is(err, context.Canceled) {
+		authenticator, err := auth.New(auth.Options{
+			TokenFile:    viper.GetString("auth-token-file"),
+			Token:        viper.GetString("auth-token"),
+			CertFile:     viper.GetString("tls-cert-file"),
+			KeyFile:      viper.GetString("tls-key-file"),
+			ClientCAFile: viper.GetString("tls-client-ca-file"),
+		})
+		if err != nil {
 			panic(err)
 		}
-		if sseServer != nil {
-			_ = sseServer.Shutdown(cmd.Context())
+		if err := serve(mcpServer, authenticator); err != nil {
+			panic(err)
 		}
 	},
 }
 
+// serve runs the transports selected with the --sse-port and --http-port flags, or
+// stdio if there are none, until one fails or the process is interrupted
+func serve(mcpServer *mcp.Server, authenticator *auth.Authenticator) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var transports []*transport
+	if ssePort := viper.GetInt("sse-port"); ssePort > 0 {
+		sseServer := mcpServer.ServeSse(viper.GetString("sse-base-url"))
+		transports = append(transports, newTransport(ssePort, sseServer, sseServer.Shutdown, authenticator))
+	}
+	if httpPort := viper.GetInt("http-port"); httpPort > 0 {
+		httpServer := mcpServer.ServeStreamableHTTP(mcp.WithSessionIdleTimeout(viper.GetDuration("http-session-timeout")))
+		transports = append(transports, newTransport(httpPort, httpServer, httpServer.Shutdown, authenticator))
+	}
+	if len(transports) == 0 {
+		if err := mcpServer.ServeStdio(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+		return nil
+	}
+	if !authenticator.Enabled() {
+		log.Printf("warning: authentication is not configured, every client reaching the server can call every tool")
+	} else if authenticator.TLSConfig() == nil {
+		log.Printf("warning: TLS is not configured, tokens are sent in clear text")
+	}
+
+	errs := make(chan error, len(transports))
+	for _, t := range transports {
+		go func() { errs <- t.start() }()
+	}
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("shutdown-timeout"))
+	defer cancel()
+	for _, t := range transports {
+		if shutdownErr := t.shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("error shutting down: %w", shutdownErr)
+		}
+	}
+	return err
+}
+
+// transport is a network transport of the MCP server, served over HTTP or HTTPS
+type transport struct {
+	srv *http.Server
+	// closeSessions ends the sessions of the transport
+	closeSessions func(context.Context) error
+}
+
+// newTransport returns a transport serving the handler on a port, behind the authenticator
+func newTransport(port int, handler http.Handler, closeSessions func(context.Context) error, authenticator *auth.Authenticator) *transport {
+	return &transport{
+		srv: &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           authenticator.Middleware(handler),
+			TLSConfig:         authenticator.TLSConfig(),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		closeSessions: closeSessions,
+	}
+}
+
+// start serves the transport until it is shut down
+func (t *transport) start() error {
+	var err error
+	if t.srv.TLSConfig != nil {
+		err = t.srv.ListenAndServeTLS("", "")
+	} else {
+		err = t.srv.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// shutdown closes the sessions and waits for the pending requests until the context
+// is done, then closes the remaining connections, such as open event streams
+func (t *transport) shutdown(ctx context.Context) error {
+	if err := t.closeSessions(ctx); err != nil {
+		return err
+	}
+	if err := t.srv.Shutdown(ctx); err != nil {
+		log.Printf("closing the connections still open on %s: %v", t.srv.Addr, err)
+		return t.srv.Close()
+	}
+	return nil
+}
+
+// setup loads the endpoint registry and configures the HTTP client shared by the
+// server and the subcommands
+func setup() (*config.Config, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	httpOptions := httpclient.DefaultOptions()
+	httpOptions.Timeout = viper.GetDuration("http-timeout")
+	httpOptions.Retries = viper.GetInt("http-retries")
+	httpOptions.CacheTTL = viper.GetDuration("cache-ttl")
+	httpOptions.Cache, err = openCache()
+	if err != nil {
+		return nil, err
+	}
+	httpclient.SetDefault(httpclient.New(httpOptions))
+	return cfg, nil
+}
+
+// loadConfig reads the endpoint registry from the file given with --config, if any
+func loadConfig() (*config.Config, error) {
+	if configFile := viper.GetString("config"); configFile != "" {
+		viper.SetConfigFile(configFile)
+		if err := viper.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %w", configFile, err)
+		}
+	}
+	return config.Load(viper.GetViper())
+}
+
+// openCache opens the response cache configured with the --cache-* flags, it returns nil if caching is disabled.
+// The cache is optional: if the default directory cannot be used, e.g. in a read-only container without
+// $HOME, the server runs without it. Only a directory set with --cache-dir has to be usable.
+func openCache() (*cache.Cache, error) {
+	if viper.GetBool("no-cache") {
+		return nil, nil
+	}
+	cacheDir := viper.GetString("cache-dir")
+	explicit := cacheDir != ""
+	if !explicit {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			log.Printf("warning: running without the response cache, cannot find the user cache directory: %v", err)
+			return nil, nil
+		}
+		cacheDir = filepath.Join(userCacheDir, version.BinaryName)
+	}
+	c, err := cache.New(cacheDir, viper.GetInt64("cache-max-size")*1024*1024)
+	if err != nil {
+		if explicit {
+			return nil, err
+		}
+		log.Printf("warning: running without the response cache, use --cache-dir to set a usable directory: %v", err)
+		return nil, nil
+	}
+	if viper.GetBool("clear-cache") {
+		if err := c.Clear(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
 func init() {
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information and quit")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "Config file describing the release controllers and Prow instances to use")
+	rootCmd.PersistentFlags().Duration("http-timeout", httpclient.DefaultOptions().Timeout, "Timeout of a single HTTP request to the release controllers and artifact hosts")
+	rootCmd.PersistentFlags().Int("http-retries", httpclient.DefaultOptions().Retries, "Number of times a failed HTTP request is retried")
+	rootCmd.PersistentFlags().String("cache-dir", "", "Directory of the response cache (default is the user cache directory)")
+	rootCmd.PersistentFlags().Int64("cache-max-size", 1024, "Maximum size of the response cache in MiB, the least recently used responses are evicted first")
+	rootCmd.PersistentFlags().Duration("cache-ttl", httpclient.DefaultOptions().CacheTTL, "How long release controller API responses are cached")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Disable the response cache")
+	rootCmd.PersistentFlags().Bool("clear-cache", false, "Remove every cached response on startup")
+	rootCmd.Flags().Int("max-response-size", mcp.DefaultMaxResponseSize/1024, "Size in KiB above which tool results are split in pages, 0 for no limit")
+	rootCmd.Flags().String("prompts-dir", "", "Directory of prompt templates (*.tmpl) overriding or adding to the built-in prompts")
+	rootCmd.Flags().StringSlice("toolsets", nil, "Only register the tools of these toolsets, among "+strings.Join(mcp.Toolsets(), ", ")+" (default all)")
+	rootCmd.Flags().StringSlice("disable-tools", nil, "Do not register these tools")
+	rootCmd.Flags().Bool("read-only", false, "Only register the tools without side effects, such as writing files")
+	rootCmd.Flags().String("export-dir", "", "Directory the export_release_data tool writes to, exports are disabled if empty")
+	rootCmd.Flags().Duration("poll-interval", mcp.DefaultPollInterval, "How often the release streams whose resources are subscribed to are polled for new or changed tags")
+	rootCmd.Flags().Duration("poll-max-backoff", mcp.DefaultPollMaxBackoff, "Longest delay between two polls of a release stream failing to be fetched")
 	rootCmd.Flags().IntP("sse-port", "", 0, "Start a SSE server on the specified port")
 	rootCmd.Flags().StringP("sse-base-url", "", "", "SSE public base URL to use when sending the endpoint message (e.g. https://example.com)")
+	rootCmd.Flags().Int("http-port", 0, "Start a streamable HTTP server on the specified port, serving the /mcp endpoint")
+	rootCmd.Flags().Duration("http-session-timeout", mcp.DefaultSessionIdleTimeout, "How long a streamable HTTP session is kept without any request")
+	rootCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "How long the SSE and HTTP servers wait for pending requests when shutting down")
+	rootCmd.Flags().String("auth-token-file", "", "YAML or JSON file listing the bearer tokens and client certificates allowed to use the SSE and HTTP servers, with their tools")
+	rootCmd.Flags().String("auth-token", "", "Bearer token allowed to call every tool on the SSE and HTTP servers (env RELEASECONTROLLER_MCP_AUTH_TOKEN)")
+	rootCmd.Flags().String("tls-cert-file", "", "Certificate to serve the SSE and HTTP servers over HTTPS")
+	rootCmd.Flags().String("tls-key-file", "", "Key of the certificate given with --tls-cert-file")
+	rootCmd.Flags().String("tls-client-ca-file", "", "CA bundle verifying the client certificates, which are then required (mTLS)")
+	_ = viper.BindPFlags(rootCmd.PersistentFlags())
 	_ = viper.BindPFlags(rootCmd.Flags())
+	_ = viper.BindEnv("auth-token", "RELEASECONTROLLER_MCP_AUTH_TOKEN")
 }
 
 func Execute() {
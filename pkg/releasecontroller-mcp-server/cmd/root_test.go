@@ -3,7 +3,10 @@ package cmd
 import (
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func captureOutput(f func() error) (string, error) {
@@ -27,3 +30,23 @@ func TestVersion(t *testing.T) {
 		return
 	}
 }
+
+func TestOpenCacheWithoutUsableDirectory(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	viper.Set("cache-dir", "")
+	c, err := openCache()
+	if err != nil || c != nil {
+		t.Errorf("expected to run without a cache when the default directory is unusable, got %v %v", c, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("cache-dir", filepath.Join(file, "cache"))
+	defer viper.Set("cache-dir", "")
+	if _, err := openCache(); err == nil {
+		t.Errorf("expected an unusable --cache-dir to be an error")
+	}
+}
@@ -1,53 +1,74 @@
 package releasecontroller
 
+import (
+	"context"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+)
+
 // ReleaseController interface
 type ReleaseController interface {
-	// ListReleaseControllers lists the available release controllers to use
-	ListReleaseControllers() string
-	// GetOKDReleaseController returns the OKD release controller URL
-	GetOKDReleaseController() string
-	// GetOCPReleaseController returns the OpenShift release controller URL
-	GetOCPReleaseController() string
-	//GetMultiReleaseController returns the multi-arch release controller URL
-	GetMultiReleaseController() string
-	//GetARM64ReleaseController returns the ARM64 release controller URL
-	GetARM64ReleaseController() string
-	// GetPPC64LEReleaseController returns the PPC64LE release controller URL
-	GetPPC64LEReleaseController() string
-	// GetS390XReleaseController returns the S390X release controller URL
-	GetS390XReleaseController() string
+	// ListReleaseControllers lists the hosts of the available release controllers to use
+	ListReleaseControllers(ctx context.Context) []string
+	// ResolveReleaseController finds a release controller by name, host or alias, e.g. "okd",
+	// "aarch64" or "4.20 ppc". Every method taking a releasecontroller accepts the same values.
+	ResolveReleaseController(ctx context.Context, alias string) (*api.ReleaseController, error)
 	// ListReleaseStreams lists all the release streams in the release controller
-	ListReleaseStreams(releasecontroller string) (string, error)
+	ListReleaseStreams(ctx context.Context, releasecontroller string) ([]string, error)
+	// ListReleaseTags lists the tags of a release stream with their phase, newest first
+	ListReleaseTags(ctx context.Context, releasecontroller, stream string) ([]api.Tag, error)
+	// GetReleaseInfo gets the verification results, upgrades and changelog of a release
+	GetReleaseInfo(ctx context.Context, releasecontroller, stream, tag string) (*api.APIReleaseInfo, error)
 	// LatestRelease gets the latest release for a given stream
-	LatestReleaseWithPhase(releasecontroller, stream string) (string, error)
+	LatestReleaseWithPhase(ctx context.Context, releasecontroller, stream string) (*api.Tag, error)
 	// LatestAcceptedRelease gets the latest accepted release for a given stream
-	LatestAcceptedRelease(releasecontroller, stream string) (string, error)
+	LatestAcceptedRelease(ctx context.Context, releasecontroller, stream string) (*api.Tag, error)
 	// LatestRejectedRelease gets the latest rejected release for a given stream
-	LatestRejectedRelease(releasecontroller, stream string) (string, error)
+	LatestRejectedRelease(ctx context.Context, releasecontroller, stream string) (*api.Tag, error)
+	// ListRecentTags lists the count most recent tags of a stream, only the ones in the given phases if any
+	ListRecentTags(ctx context.Context, releasecontroller, stream string, count int, phases []string) ([]api.Tag, error)
+	// GetStreamHealth gets the acceptance history and the job pass rates of the count most recent payloads of a stream
+	GetStreamHealth(ctx context.Context, releasecontroller, stream string, count int) (*api.StreamHealth, error)
+	// GetJobMatrix gets the state of every verification job in the count most recent payloads of a stream
+	GetJobMatrix(ctx context.Context, releasecontroller, stream string, count int) (*api.JobMatrix, error)
+	// BisectJobFailure finds the payload at which a job started failing among the count most recent payloads of a stream
+	BisectJobFailure(ctx context.Context, releasecontroller, stream, job string, count int) (*api.JobBisection, error)
 	// ListFailedJobsInRelease lists all the failed jobs in a given release
-	ListFailedJobsInRelease(releasecontroller, stream, tag string) (string, error)
+	ListFailedJobsInRelease(ctx context.Context, releasecontroller, stream, tag string) ([]api.VerificationJob, error)
+	// GetPayloadStatus gets the state of every verification job of a release, and whether it can still be accepted
+	GetPayloadStatus(ctx context.Context, releasecontroller, stream, tag string) (*api.PayloadStatus, error)
 	// ListComponentsInRelease lists the kubectl, kubernetes, coreos and tests versions in the release
-	ListComponentsInRelease(releasecontroller, stream, tag string) (string, error)
-	// ListTestFailuresForRelease gets the failing tests for the particular job
-	ListTestFailuresForRelease(prowurl string) (string, error)
+	ListComponentsInRelease(ctx context.Context, releasecontroller, stream, tag string) ([]api.Component, error)
+	// CompareReleases gets what changed between two releases of a release controller, which
+	// do not need to be consecutive nor in the same stream
+	CompareReleases(ctx context.Context, releasecontroller, from, to string) (*api.ReleaseComparison, error)
+	// ListUpgradesForRelease lists the upgrades tested into and out of a release with their results
+	ListUpgradesForRelease(ctx context.Context, releasecontroller, stream, tag string) (*api.ReleaseUpgrades, error)
+	// GetUpgradeEdge gets the results of the upgrade tests from a release to a release of the
+	// stream, failing if no upgrade between them was ever recorded
+	GetUpgradeEdge(ctx context.Context, releasecontroller, stream, from, to string) (*api.UpgradeEdge, error)
+	// ListTestFailuresForRelease gets the failing tests for the particular job. Like all the
+	// methods taking a prowurl, it also accepts a local artifact root (directory or .tar.gz)
+	ListTestFailuresForRelease(ctx context.Context, prowurl string) (*api.TestResults, error)
 	//GetFlakyTestsForRelease gets the flaky tests for the particular job
-	GetFlakyTestsForRelease(prowurl string) (string, error)
+	GetFlakyTestsForRelease(ctx context.Context, prowurl string) (*api.TestResults, error)
 	// GetRiskAnalysisData gets the risk analysis data for the particular job
-	GetRiskAnalysisData(prowurl string) (string, error)
+	GetRiskAnalysisData(ctx context.Context, prowurl string) (*api.RiskAnalysis, error)
 	// GetSpyglassDataRelevantToTestFailure gets the spyglass data relevant to a test failure
-	GetSpyglassDataRelevantToTestFailure(prowurl string, testName string) (string, error)
+	GetSpyglassDataRelevantToTestFailure(ctx context.Context, prowurl string, testName string) ([]api.SpyglassEvent, error)
 	//GetTopLevelBuildLog gets the top-level build log for a given Prow job URL
-	GetTopLevelBuildLog(prowurl string, LogCompactionThreshold string) (string, error)
+	GetTopLevelBuildLog(ctx context.Context, prowurl string, LogCompactionThreshold string) (*api.JobLog, error)
 	// AnalyzeJobFailuresForRelease gets the build log file for the particular job
-	AnalyzeJobFailuresForRelease(url string, LogCompactionThreshold string) (string, error)
+	AnalyzeJobFailuresForRelease(ctx context.Context, url string, LogCompactionThreshold string) (*api.JobLog, error)
 	// List issues which are features from updated images commits - excludes OCPBUGS/CVEs
-	ListFeaturesFromUpdatedImagesCommits(releasecontroller, stream, tag string) (string, error)
+	ListFeaturesFromUpdatedImagesCommits(ctx context.Context, releasecontroller, stream, tag string) ([]api.Issue, error)
 	// List issues which are bugs from updated images commits
-	ListBugsFromUpdatedImagesCommits(releasecontroller, stream, tag string) (string, error)
+	ListBugsFromUpdatedImagesCommits(ctx context.Context, releasecontroller, stream, tag string) ([]api.Issue, error)
 	// List issues which are CVEs from updated images commits
-	ListCVEsFromUpdatedImagesCommits(releasecontroller, stream, tag string) (string, error)
+	ListCVEsFromUpdatedImagesCommits(ctx context.Context, releasecontroller, stream, tag string) ([]api.Issue, error)
 }
 
-func NewReleaseController() ReleaseController {
-	return newReleaseControllerCli()
+func NewReleaseController(cfg *config.Config) ReleaseController {
+	return newReleaseControllerCli(cfg)
 }
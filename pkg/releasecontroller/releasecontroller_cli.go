@@ -1,555 +1,1020 @@
 package releasecontroller
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"net/url"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/releasename"
 	utils "github.com/Prashanth684/releasecontroller-mcp-server/pkg/utils"
 )
 
-const (
-	OKDReleaseController     = "amd64.origin.releases.ci.openshift.org"
-	OCPReleaseController     = "amd64.ocp.releases.ci.openshift.org"
-	MultiReleaseController   = "multi.ocp.releases.ci.openshift.org"
-	ARM64ReleaseController   = "arm64.ocp.releases.ci.openshift.org"
-	PPC64LEReleaseController = "ppc64le.ocp.releases.ci.openshift.org"
-	S390XReleaseController   = "s390x.ocp.releases.ci.openshift.org"
-)
-
 type releaseControllerCli struct {
-	releaseControllers []string
+	config *config.Config
 }
 
 // ListReleaseControllers lists the available release controllers to use
-func (r *releaseControllerCli) ListReleaseControllers() string {
-	return strings.Join(r.releaseControllers, ",")
+func (r *releaseControllerCli) ListReleaseControllers(ctx context.Context) []string {
+	return r.config.ReleaseControllerHosts()
 }
 
-// GetOKDReleaseController returns the OKD release controller host
-func (r *releaseControllerCli) GetOKDReleaseController() string {
-	return OKDReleaseController
+// ResolveReleaseController finds a release controller of the registry by name, host or alias
+func (r *releaseControllerCli) ResolveReleaseController(ctx context.Context, alias string) (*api.ReleaseController, error) {
+	rc, err := r.config.Resolve(alias)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ReleaseController{
+		Name:         rc.Name,
+		Host:         rc.Host(),
+		URL:          rc.URL,
+		Product:      rc.Product,
+		Architecture: rc.Architecture,
+		Aliases:      rc.Aliases,
+	}, nil
 }
 
-// GetOCPReleaseController returns the OCP release controller host
-func (r *releaseControllerCli) GetOCPReleaseController() string {
-	return OCPReleaseController
+// ListReleaseStreams lists all the releases from all the streams in the release controller
+func (r *releaseControllerCli) ListReleaseStreams(ctx context.Context, releasecontroller string) ([]string, error) {
+	baseURL, err := r.config.ReleaseControllerURL(releasecontroller)
+	if err != nil {
+		return nil, err
+	}
+	data, err := utils.FetchJSONBytes(ctx, fmt.Sprintf("%s/api/v1/releasestreams/all", baseURL))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release streams: %w", err)
+	}
+	topKeys, err := utils.FetchTopLevelKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching top-level keys: %w", err)
+	}
+	sort.Strings(topKeys)
+	return topKeys, nil
 }
 
-// GetMultiReleaseController returns the Multi release controller host
-func (r *releaseControllerCli) GetMultiReleaseController() string {
-	return MultiReleaseController
+// ListReleaseTags lists the tags of a release stream with their phase, newest first
+func (r *releaseControllerCli) ListReleaseTags(ctx context.Context, releasecontroller, stream string) ([]api.Tag, error) {
+	release, err := r.releaseTags(ctx, releasecontroller, stream)
+	if err != nil {
+		return nil, err
+	}
+	return release.Tags, nil
 }
 
-// GetARM64ReleaseController returns the ARM64 release controller host
-func (r *releaseControllerCli) GetARM64ReleaseController() string {
-	return ARM64ReleaseController
+// releaseTags fetches the tags of a release stream, sorted newest first by release name
+func (r *releaseControllerCli) releaseTags(ctx context.Context, releasecontroller, stream string) (*api.Release, error) {
+	baseURL, err := r.config.ReleaseControllerURL(releasecontroller)
+	if err != nil {
+		return nil, err
+	}
+	data, err := utils.FetchJSONBytes(ctx, fmt.Sprintf("%s/api/v1/releasestream/%s/tags", baseURL, stream))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release tags: %w", err)
+	}
+	release, err := utils.ParseRelease(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release data: %w", err)
+	}
+	slices.SortStableFunc(release.Tags, func(a, b api.Tag) int {
+		return releasename.Compare(b.Name, a.Name)
+	})
+	return release, nil
 }
 
-// GetPPC64LEReleaseController returns the PPC64LE release controller host
-func (r *releaseControllerCli) GetPPC64LEReleaseController() string {
-	return PPC64LEReleaseController
+func (r *releaseControllerCli) LatestReleaseWithPhase(ctx context.Context, releasecontroller, stream string) (*api.Tag, error) {
+	release, err := r.releaseTags(ctx, releasecontroller, stream)
+	if err != nil {
+		return nil, err
+	}
+	if len(release.Tags) == 0 {
+		return nil, fmt.Errorf("no tags found in stream %s", stream)
+	}
+	return &release.Tags[0], nil
 }
 
-// GetS390XReleaseController returns the S390X release controller host
-func (r *releaseControllerCli) GetS390XReleaseController() string {
-	return S390XReleaseController
+// LatestAcceptedRelease gets the latest accepted release for a given stream
+func (r *releaseControllerCli) LatestAcceptedRelease(ctx context.Context, releasecontroller, stream string) (*api.Tag, error) {
+	release, err := r.releaseTags(ctx, releasecontroller, stream)
+	if err != nil {
+		return nil, err
+	}
+	acceptedTags := utils.FilterAcceptedTags(release)
+	if len(acceptedTags) == 0 {
+		return nil, errors.New("no accepted tags found")
+	}
+	return &acceptedTags[0], nil
 }
 
-// ListReleaseStreams lists all the releases from all the streams in the release controller
-func (r *releaseControllerCli) ListReleaseStreams(releasecontroller string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestreams/all", releasecontroller))
+// LatestRejectedRelease gets the latest rejected release for a given stream
+func (r *releaseControllerCli) LatestRejectedRelease(ctx context.Context, releasecontroller, stream string) (*api.Tag, error) {
+	release, err := r.releaseTags(ctx, releasecontroller, stream)
 	if err != nil {
-		return "", fmt.Errorf("error fetching release streams: %w", err)
+		return nil, err
 	}
-	topKeys, err := utils.FetchTopLevelKeys(data)
-	if err != nil {
-		return "", fmt.Errorf("error fetching top-level keys: %w", err)
+	rejectedTags := utils.FilterRejectedTags(release)
+	if len(rejectedTags) == 0 {
+		return nil, errors.New("no rejected tags found")
 	}
-	return strings.Join(topKeys, ", "), nil
+	return &rejectedTags[0], nil
 }
 
-func (r *releaseControllerCli) LatestReleaseWithPhase(releasecontroller, stream string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/tags", releasecontroller, stream))
+// ListRecentTags lists the count most recent tags of a stream, only the ones in the
+// given phases if any
+func (r *releaseControllerCli) ListRecentTags(ctx context.Context, releasecontroller, stream string, count int, phases []string) ([]api.Tag, error) {
+	release, err := r.releaseTags(ctx, releasecontroller, stream)
 	if err != nil {
-		return "", fmt.Errorf("error fetching release tags: %w", err)
+		return nil, err
 	}
-	release, err := utils.ParseRelease(data)
-	if err != nil {
-		return "", fmt.Errorf("error parsing release data: %w", err)
+	tags := []api.Tag{}
+	for _, tag := range release.Tags {
+		if len(tags) == count {
+			break
+		}
+		if len(phases) == 0 || slices.ContainsFunc(phases, func(phase string) bool { return strings.EqualFold(phase, tag.Phase) }) {
+			tags = append(tags, tag)
+		}
 	}
-	latestRelease := release.Tags[0]
-	// combine the output of latestRelease with the stream
-	full := fmt.Sprintf("%s, %s", latestRelease.Name, latestRelease.Phase)
-	return full, nil
+	return tags, nil
 }
 
-// LatestAcceptedRelease gets the latest accepted release for a given stream
-func (r *releaseControllerCli) LatestAcceptedRelease(releasecontroller, stream string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/tags", releasecontroller, stream))
+// maxConcurrentFetches bounds the release controller requests made at once by a single call
+const maxConcurrentFetches = 8
+
+// GetStreamHealth gets the acceptance history and the job pass rates of the most recent payloads of a stream
+func (r *releaseControllerCli) GetStreamHealth(ctx context.Context, releasecontroller, stream string, count int) (*api.StreamHealth, error) {
+	payloads, err := r.recentReleaseInfos(ctx, releasecontroller, stream, count)
 	if err != nil {
-		return "", fmt.Errorf("error fetching release tags: %w", err)
+		return nil, err
 	}
-	release, err := utils.ParseRelease(data)
+	// The last accepted payload may be older than the ones looked at, when the stream
+	// rejected every one of them
+	var lastAccepted *api.APIReleaseInfo
+	if i := slices.IndexFunc(payloads.tags, func(tag api.Tag) bool { return tag.Phase == "Accepted" }); i >= len(payloads.infos) {
+		lastAccepted, _ = r.releaseInfo(ctx, releasecontroller, stream, payloads.tags[i].Name)
+	}
+	return streamHealth(stream, payloads, lastAccepted, time.Now()), nil
+}
+
+// GetJobMatrix gets the state of every verification job in the most recent payloads of a stream
+func (r *releaseControllerCli) GetJobMatrix(ctx context.Context, releasecontroller, stream string, count int) (*api.JobMatrix, error) {
+	payloads, err := r.recentReleaseInfos(ctx, releasecontroller, stream, count)
 	if err != nil {
-		return "", fmt.Errorf("error parsing release data: %w", err)
+		return nil, err
 	}
-	acceptedTags := utils.FilterAcceptedTags(release)
-	acceptedTagsJSON, err := json.MarshalIndent(acceptedTags, "", "  ")
+	return jobMatrix(stream, payloads), nil
+}
+
+// BisectJobFailure finds the payload at which a verification job started failing among the most recent
+// payloads of a stream, and the changes since the last payload in which it passed
+func (r *releaseControllerCli) BisectJobFailure(ctx context.Context, releasecontroller, stream, job string, count int) (*api.JobBisection, error) {
+	payloads, err := r.recentReleaseInfos(ctx, releasecontroller, stream, count)
 	if err != nil {
-		return "", fmt.Errorf("error marshaling accepted tags data: %w", err)
+		return nil, err
+	}
+	bisection, err := bisectJob(stream, job, payloads)
+	if err != nil || bisection.LastPassed == "" {
+		return bisection, err
 	}
-	err = os.WriteFile("latest_accepted_tags.json", acceptedTagsJSON, 0644)
+	bisection.Suspects, err = r.CompareReleases(ctx, releasecontroller, bisection.LastPassed, bisection.FirstFailed)
 	if err != nil {
-		return "", fmt.Errorf("error writing accepted tags data to file: %w", err)
+		return nil, err
 	}
-	if len(acceptedTags) == 0 {
-		return "", errors.New("no accepted tags found")
+	return bisection, nil
+}
+
+// bisectJob finds the current failure streak of a job in the most recent payloads
+func bisectJob(stream, job string, payloads *payloads) (*api.JobBisection, error) {
+	matrix := jobMatrix(stream, payloads)
+	tags := payloads.recent()
+	i := slices.IndexFunc(matrix.Jobs, func(row api.MatrixJob) bool { return row.Name == job })
+	if i < 0 {
+		return nil, fmt.Errorf("job %s did not run in the last %d payloads of %s", job, len(tags), stream)
+	}
+	row := matrix.Jobs[i]
+	if row.FailingSince == "" {
+		return nil, fmt.Errorf("job %s is not failing in the last %d payloads of %s, its latest finished run succeeded or it has not finished yet", job, len(tags), stream)
+	}
+	bisection := &api.JobBisection{Stream: stream, Job: job, Kind: row.Kind, Unavailable: payloads.unavailable()}
+	for i, cell := range row.Cells {
+		switch cell.State {
+		case "Failed":
+			bisection.Failures++
+			bisection.FirstFailed, bisection.FirstFailedURL = tags[i].Name, cell.URL
+		case "Succeeded":
+			bisection.LastPassed, bisection.LastPassedURL = tags[i].Name, cell.URL
+			return bisection, nil
+		}
 	}
-	var latestAcceptedTag string
-	for _, tag := range acceptedTags {
-		if latestAcceptedTag == "" || tag.Name > latestAcceptedTag {
-			latestAcceptedTag = tag.Name
+	return bisection, nil
+}
+
+// payloads are the tags of a stream with the release info of the most recent ones
+type payloads struct {
+	// tags are all the tags of the stream, newest first
+	tags []api.Tag
+	// infos are the release info of the most recent tags, nil for the ones which could not be fetched
+	infos []*api.APIReleaseInfo
+	// errs are the errors fetching the release info, in the same order as infos
+	errs []error
+}
+
+// recent returns the tags whose release info was fetched
+func (p *payloads) recent() []api.Tag {
+	return p.tags[:len(p.infos)]
+}
+
+// unavailable lists the most recent payloads whose release info could not be fetched
+func (p *payloads) unavailable() []api.UnavailablePayload {
+	var unavailable []api.UnavailablePayload
+	for i, err := range p.errs {
+		if err != nil {
+			unavailable = append(unavailable, api.UnavailablePayload{Name: p.tags[i].Name, Error: err.Error()})
 		}
 	}
-	return latestAcceptedTag, nil
+	return unavailable
 }
 
-// LatestRejectedRelease gets the latest rejected release for a given stream
-func (r *releaseControllerCli) LatestRejectedRelease(releasecontroller, stream string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/tags", releasecontroller, stream))
-	if err != nil {
-		return "", fmt.Errorf("error fetching release tags: %w", err)
+// recentReleaseInfos fetches the release info of the count most recent tags of a stream
+// concurrently. The info of a tag is nil if it could not be fetched, e.g. because the
+// payload was garbage collected since the tags were listed, but it is an error if none
+// of them could be.
+func (r *releaseControllerCli) recentReleaseInfos(ctx context.Context, releasecontroller, stream string, count int) (*payloads, error) {
+	release, err := r.releaseTags(ctx, releasecontroller, stream)
+	if err != nil {
+		return nil, err
+	}
+	n := min(count, len(release.Tags))
+	p := &payloads{tags: release.Tags, infos: make([]*api.APIReleaseInfo, n), errs: make([]error, n)}
+	sem := make(chan struct{}, maxConcurrentFetches)
+	var wg sync.WaitGroup
+	for i, tag := range p.recent() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			p.infos[i], p.errs[i] = r.releaseInfo(ctx, releasecontroller, stream, tag.Name)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if n > 0 && !slices.Contains(p.errs, nil) {
+		return nil, fmt.Errorf("error fetching the release info of all the %d most recent payloads of %s: %w", n, stream, errors.Join(p.errs...))
+	}
+	return p, nil
+}
+
+// streamHealth computes the health of a stream from its most recent payloads at the time
+// now. The last accepted payload is searched among all the tags, lastAccepted being its
+// release info when it is older than the payloads looked at.
+func streamHealth(stream string, payloads *payloads, lastAccepted *api.APIReleaseInfo, now time.Time) *api.StreamHealth {
+	tags, infos := payloads.recent(), payloads.infos
+	health := &api.StreamHealth{Stream: stream, Payloads: len(tags), Jobs: []api.JobPassRate{}, Unavailable: payloads.unavailable()}
+	streak, counting := 0, true
+	// Walk from the oldest payload to compute the streaks
+	for i := len(tags) - 1; i >= 0; i-- {
+		switch tags[i].Phase {
+		case "Accepted":
+			health.Accepted++
+			streak = 0
+		case "Rejected":
+			health.Rejected++
+			streak++
+			health.LongestRejectionStreak = max(health.LongestRejectionStreak, streak)
+		default:
+			health.Other++
+		}
 	}
-	release, err := utils.ParseRelease(data)
-	if err != nil {
-		return "", fmt.Errorf("error parsing release data: %w", err)
+	for _, tag := range tags {
+		if !counting {
+			break
+		}
+		switch tag.Phase {
+		case "Accepted":
+			counting = false
+		case "Rejected":
+			health.CurrentRejectionStreak++
+		}
 	}
-	rejectedTags := utils.FilterRejectedTags(release)
-	if len(rejectedTags) == 0 {
-		return "", errors.New("no rejected tags found")
+	if decided := health.Accepted + health.Rejected; decided > 0 {
+		health.AcceptanceRate = 100 * float64(health.Accepted) / float64(decided)
+	}
+	if i := slices.IndexFunc(payloads.tags, func(tag api.Tag) bool { return tag.Phase == "Accepted" }); i >= 0 {
+		if i < len(infos) {
+			lastAccepted = infos[i]
+		}
+		health.LastAccepted = payloads.tags[i].Name
+		health.LastAcceptedAt = payloadCreated(health.LastAccepted, lastAccepted)
+		if health.LastAcceptedAt != nil {
+			health.SinceLastAccepted = now.Sub(*health.LastAcceptedAt).Round(time.Minute).String()
+		}
 	}
-	var latestRejectedTag string
-	for _, tag := range rejectedTags {
-		if latestRejectedTag == "" || tag.Name > latestRejectedTag {
-			latestRejectedTag = tag.Name
+
+	jobs := map[string]*api.JobPassRate{}
+	for _, info := range infos {
+		if info == nil || info.Results == nil {
+			continue
+		}
+		for _, group := range []struct {
+			kind     api.JobKind
+			statuses api.VerificationStatusMap
+		}{
+			{api.JobKindBlocking, info.Results.BlockingJobs},
+			{api.JobKindInforming, info.Results.InformingJobs},
+		} {
+			for name, status := range group.statuses {
+				if status == nil || status.State != "Succeeded" && status.State != "Failed" {
+					continue
+				}
+				key := string(group.kind) + "/" + name
+				job, ok := jobs[key]
+				if !ok {
+					job = &api.JobPassRate{Name: name, Kind: group.kind}
+					jobs[key] = job
+				}
+				job.Runs++
+				if status.State == "Succeeded" {
+					job.Passed++
+				} else {
+					job.Failed++
+				}
+			}
 		}
 	}
-	return latestRejectedTag, nil
+	for _, job := range jobs {
+		job.PassRate = 100 * float64(job.Passed) / float64(job.Runs)
+		health.Jobs = append(health.Jobs, *job)
+	}
+	kindOrder := []api.JobKind{api.JobKindBlocking, api.JobKindInforming}
+	slices.SortFunc(health.Jobs, func(a, b api.JobPassRate) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(kindOrder, a.Kind), slices.Index(kindOrder, b.Kind)),
+			cmp.Compare(a.PassRate, b.PassRate),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	return health
 }
 
-// ListFailedJobsInRelease lists all the failed jobs in a given release
-func (r *releaseControllerCli) ListFailedJobsInRelease(releasecontroller, stream, tag string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/release/%s", releasecontroller, stream, tag))
-	if err != nil {
-		return "", fmt.Errorf("error fetching release info: %w", err)
+// jobMatrix lays out the state of every verification job, blocking jobs first, in the
+// most recent payloads, newest first
+func jobMatrix(stream string, payloads *payloads) *api.JobMatrix {
+	tags, infos := payloads.recent(), payloads.infos
+	matrix := &api.JobMatrix{Stream: stream, Payloads: []api.MatrixPayload{}, Jobs: []api.MatrixJob{}}
+	rows := map[string]*api.MatrixJob{}
+	for i, tag := range tags {
+		payload := api.MatrixPayload{Name: tag.Name, Phase: tag.Phase, Available: infos[i] != nil}
+		if i < len(payloads.errs) && payloads.errs[i] != nil {
+			payload.Error = payloads.errs[i].Error()
+		}
+		matrix.Payloads = append(matrix.Payloads, payload)
+		if infos[i] == nil || infos[i].Results == nil {
+			continue
+		}
+		for kind, statuses := range map[api.JobKind]api.VerificationStatusMap{
+			api.JobKindBlocking:  infos[i].Results.BlockingJobs,
+			api.JobKindInforming: infos[i].Results.InformingJobs,
+		} {
+			for name, status := range statuses {
+				if status == nil {
+					continue
+				}
+				key := string(kind) + "/" + name
+				row, ok := rows[key]
+				if !ok {
+					row = &api.MatrixJob{Name: name, Kind: kind, Cells: make([]api.MatrixCell, len(tags))}
+					rows[key] = row
+				}
+				row.Cells[i] = api.MatrixCell{State: status.State, URL: status.URL, Retries: status.Retries}
+			}
+		}
 	}
-	info, err := utils.ParseAPIReleaseInfo(data)
+	for _, row := range rows {
+		row.FailingSince = failingSince(tags, row.Cells)
+		matrix.Jobs = append(matrix.Jobs, *row)
+	}
+	kindOrder := []api.JobKind{api.JobKindBlocking, api.JobKindInforming}
+	slices.SortFunc(matrix.Jobs, func(a, b api.MatrixJob) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(kindOrder, a.Kind), slices.Index(kindOrder, b.Kind)),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	return matrix
+}
+
+// failingSince returns the oldest payload of the failures a job has had in a row in the
+// most recent payloads, skipping the ones where it is pending or did not run, empty if
+// its latest finished run succeeded
+func failingSince(tags []api.Tag, cells []api.MatrixCell) string {
+	since := ""
+	for i, cell := range cells {
+		switch cell.State {
+		case "Failed":
+			since = tags[i].Name
+		case "Succeeded":
+			return since
+		}
+	}
+	return since
+}
+
+// payloadCreated returns when a payload was created, from its changelog or its name
+func payloadCreated(name string, info *api.APIReleaseInfo) *time.Time {
+	if info != nil && !info.ChangeLogJson.To.Created.IsZero() {
+		created := info.ChangeLogJson.To.Created
+		return &created
+	}
+	if parsed, err := releasename.Parse(name); err == nil && !parsed.Timestamp.IsZero() {
+		return &parsed.Timestamp
+	}
+	return nil
+}
+
+// ListFailedJobsInRelease lists all the failed jobs in a given release, blocking jobs first
+func (r *releaseControllerCli) ListFailedJobsInRelease(ctx context.Context, releasecontroller, stream, tag string) ([]api.VerificationJob, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
+	if err != nil {
+		return nil, err
+	}
+	failedJobs := []api.VerificationJob{}
+	if info.Results == nil {
+		return failedJobs, nil
+	}
+	for _, jobs := range []struct {
+		kind     api.JobKind
+		statuses api.VerificationStatusMap
+	}{
+		{api.JobKindBlocking, info.Results.BlockingJobs},
+		{api.JobKindInforming, info.Results.InformingJobs},
+	} {
+		var names []string
+		for jobName, status := range jobs.statuses {
+			if status == nil {
+				continue
+			}
+			if status.State == "Failed" {
+				names = append(names, jobName)
+			}
+		}
+		sort.Strings(names)
+		for _, jobName := range names {
+			status := jobs.statuses[jobName]
+			failedJobs = append(failedJobs, api.VerificationJob{Name: jobName, Kind: jobs.kind, State: status.State, URL: status.URL, Retries: status.Retries, Outcome: jobOutcome(status)})
+		}
+	}
+	return failedJobs, nil
+}
+
+// GetPayloadStatus gets the state of every verification job of a release
+func (r *releaseControllerCli) GetPayloadStatus(ctx context.Context, releasecontroller, stream, tag string) (*api.PayloadStatus, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
 	if err != nil {
-		return "", fmt.Errorf("error parsing release info: %w", err)
+		return nil, err
 	}
-	var failedJobs []string
-	for jobName, status := range info.Results.BlockingJobs {
-		if status.State == "Failed" {
-			failedJobs = append(failedJobs, fmt.Sprintf("%s: %s", jobName, status.URL))
+	return payloadStatus(info, time.Now()), nil
+}
+
+// jobOutcome classifies the state of a verification job with its retries. The release
+// controller only keeps the URL of the last attempt, so the earlier attempts cannot be linked.
+func jobOutcome(status *api.VerificationStatus) api.JobOutcome {
+	switch {
+	case status.State == "Succeeded" && status.Retries == 0:
+		return api.JobOutcomePassedFirstTry
+	case status.State == "Succeeded":
+		return api.JobOutcomePassedAfterRetry
+	case status.State == "Failed" && status.Retries == 0:
+		return api.JobOutcomeFailed
+	case status.State == "Failed":
+		return api.JobOutcomeExhaustedRetries
+	case status.State == "Pending" && status.Retries == 0:
+		return api.JobOutcomePending
+	case status.State == "Pending":
+		return api.JobOutcomeRetrying
+	}
+	return ""
+}
+
+// jobStateOrder orders the jobs of a payload status, the unknown states last
+var jobStateOrder = []string{"Failed", "Pending", "Succeeded"}
+
+// payloadStatus lists the verification jobs of a release at the time now and tells whether
+// it can be accepted
+func payloadStatus(info *api.APIReleaseInfo, now time.Time) *api.PayloadStatus {
+	status := &api.PayloadStatus{Release: info.Name, Phase: info.Phase, Jobs: []api.PayloadJob{}}
+	if info.Results != nil {
+		for _, jobs := range []struct {
+			kind     api.JobKind
+			statuses api.VerificationStatusMap
+		}{
+			{api.JobKindBlocking, info.Results.BlockingJobs},
+			{api.JobKindInforming, info.Results.InformingJobs},
+			{api.JobKindUnknown, info.Results.PendingJobs},
+		} {
+			for name, job := range jobs.statuses {
+				if job == nil {
+					continue
+				}
+				payloadJob := api.PayloadJob{Name: name, Kind: jobs.kind, State: job.State, URL: job.URL, Retries: job.Retries, Outcome: jobOutcome(job), Since: job.TransitionTime}
+				if job.State == "Pending" && job.TransitionTime != nil {
+					payloadJob.RunningFor = now.Sub(*job.TransitionTime).Round(time.Minute).String()
+				}
+				status.Jobs = append(status.Jobs, payloadJob)
+			}
 		}
 	}
-	for jobName, status := range info.Results.InformingJobs {
-		if status.State == "Failed" {
-			failedJobs = append(failedJobs, fmt.Sprintf("%s: %s", jobName, status.URL))
+	kindOrder := []api.JobKind{api.JobKindBlocking, api.JobKindInforming, api.JobKindUnknown}
+	stateIndex := func(state string) int {
+		if i := slices.Index(jobStateOrder, state); i >= 0 {
+			return i
+		}
+		return len(jobStateOrder)
+	}
+	slices.SortFunc(status.Jobs, func(a, b api.PayloadJob) int {
+		return cmp.Or(
+			cmp.Compare(stateIndex(a.State), stateIndex(b.State)),
+			cmp.Compare(slices.Index(kindOrder, a.Kind), slices.Index(kindOrder, b.Kind)),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+
+	var failed, running []string
+	notStarted := 0
+	for _, job := range status.Jobs {
+		switch {
+		case job.Kind == api.JobKindUnknown:
+			notStarted++
+		case job.Kind != api.JobKindBlocking:
+		case job.State == "Failed":
+			failed = append(failed, job.Name)
+		case job.State != "Succeeded":
+			running = append(running, job.Name)
 		}
 	}
-	if len(failedJobs) == 0 {
-		return "No failed jobs found", nil
+	switch {
+	case info.Phase == "Accepted":
+		status.CanBeAccepted, status.Verdict = true, "The payload was accepted"
+	case info.Phase == "Rejected" || info.Phase == "Failed":
+		status.Verdict = fmt.Sprintf("The payload was %s", strings.ToLower(info.Phase))
+	case len(failed) > 0:
+		status.Verdict = fmt.Sprintf("The payload cannot be accepted, blocking jobs failed: %s", strings.Join(failed, ", "))
+	case len(running) > 0:
+		status.CanBeAccepted, status.Verdict = true, fmt.Sprintf("The payload can still be accepted, waiting for %d blocking jobs: %s", len(running), strings.Join(running, ", "))
+	case notStarted > 0:
+		status.CanBeAccepted, status.Verdict = true, fmt.Sprintf("The payload can still be accepted, waiting for %d jobs which have not started yet", notStarted)
+	default:
+		status.CanBeAccepted, status.Verdict = true, "Every blocking job succeeded, the payload should be accepted"
 	}
-	return strings.Join(failedJobs, "\n"), nil
+	return status
 }
 
 // ListComponentsInRelease lists the kubectl, kubernetes, coreos and tests versions in the release
-func (r *releaseControllerCli) ListComponentsInRelease(releasecontroller, stream, tag string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/release/%s", releasecontroller, stream, tag))
+func (r *releaseControllerCli) ListComponentsInRelease(ctx context.Context, releasecontroller, stream, tag string) ([]api.Component, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
 	if err != nil {
-		return "", fmt.Errorf("error fetching release info: %w", err)
+		return nil, err
 	}
-	info, err := utils.ParseAPIReleaseInfo(data)
+	components := []api.Component{}
+	for _, component := range info.ChangeLogJson.Components {
+		components = append(components, api.Component{Name: component.Name, Version: component.Version})
+	}
+	return components, nil
+}
+
+// CompareReleases gets the changelog between two releases from the release controller
+func (r *releaseControllerCli) CompareReleases(ctx context.Context, releasecontroller, from, to string) (*api.ReleaseComparison, error) {
+	baseURL, err := r.config.ReleaseControllerURL(releasecontroller)
 	if err != nil {
-		return "", fmt.Errorf("error parsing release info: %w", err)
+		return nil, err
 	}
-	var components []string
-	for _, component := range info.ChangeLogJson.Components {
-		components = append(components, fmt.Sprintf("%s: %s", component.Name, component.Version))
+	data, err := utils.FetchJSONBytes(ctx, fmt.Sprintf("%s/changelog?from=%s&to=%s&format=json", baseURL, url.QueryEscape(from), url.QueryEscape(to)))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching changelog from %s to %s: %w", from, to, err)
 	}
-	if len(components) == 0 {
-		return "No components found", nil
+	changeLog, err := utils.ParseChangeLog(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing changelog from %s to %s: %w", from, to, err)
 	}
-	return strings.Join(components, "\n"), nil
+	return compareReleases(from, to, changeLog), nil
 }
 
-// ListTestFailuresForRelease gets the failing tests for the particular job
-func (r *releaseControllerCli) ListTestFailuresForRelease(prowurl string) (string, error) {
-	logsPath := utils.ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
+// compareReleases summarizes a changelog, merging the commits found in several images
+func compareReleases(from, to string, changeLog *api.ChangeLog) *api.ReleaseComparison {
+	comparison := &api.ReleaseComparison{
+		From:          from,
+		To:            to,
+		Components:    []api.ComponentChange{},
+		NewImages:     []string{},
+		RemovedImages: []string{},
+		UpdatedImages: []api.ImageChange{},
+		Commits:       []api.Commit{},
+		Issues:        []api.Issue{},
+	}
+	for _, component := range changeLog.Components {
+		if component.From != "" && component.From == component.Version {
+			continue
+		}
+		comparison.Components = append(comparison.Components, api.ComponentChange{Name: component.Name, From: component.From, To: component.Version, DiffURL: component.DiffUrl})
+	}
+	for _, image := range changeLog.NewImages {
+		comparison.NewImages = append(comparison.NewImages, image.Name)
+	}
+	for _, image := range changeLog.RemovedImages {
+		comparison.RemovedImages = append(comparison.RemovedImages, image.Name)
+	}
+	seen := map[string]bool{}
+	issues := map[string]string{}
+	for _, image := range changeLog.UpdatedImages {
+		comparison.UpdatedImages = append(comparison.UpdatedImages, api.ImageChange{Name: image.Name, Commits: len(image.Commits), FullChangeLog: image.FullChangeLog})
+		for _, commit := range image.Commits {
+			link := cmp.Or(commit.PullURL, commit.CommitURL)
+			key := cmp.Or(link, image.Name+"/"+commit.Subject)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			var ids []string
+			for _, refs := range []map[string]string{commit.Issues, commit.Bugs} {
+				for id, issueURL := range refs {
+					if !slices.Contains(ids, id) {
+						ids = append(ids, id)
+					}
+					issues[id] = issueURL
+				}
+			}
+			sort.Strings(ids)
+			comparison.Commits = append(comparison.Commits, api.Commit{Image: image.Name, Subject: commit.Subject, URL: link, Issues: ids})
+		}
 	}
-	joburl := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", logsPath)
-	data, err := utils.FetchURL(joburl)
-	if err != nil {
-		return "", fmt.Errorf("error fetching job log: %w", err)
+	for id, issueURL := range issues {
+		comparison.Issues = append(comparison.Issues, api.Issue{ID: id, URL: issueURL})
 	}
-	stepName, err := utils.ExtractStepName(data)
+	sort.Slice(comparison.Issues, func(i, j int) bool { return comparison.Issues[i].ID < comparison.Issues[j].ID })
+	return comparison
+}
+
+// ListUpgradesForRelease lists the upgrades tested into and out of a release
+func (r *releaseControllerCli) ListUpgradesForRelease(ctx context.Context, releasecontroller, stream, tag string) (*api.ReleaseUpgrades, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
 	if err != nil {
-		return "", fmt.Errorf("Could not find failure step - not a test run", err)
+		return nil, err
+	}
+	upgrades := &api.ReleaseUpgrades{Release: tag, To: []api.UpgradeEdge{}, From: []api.UpgradeEdge{}}
+	for _, history := range info.UpgradesTo {
+		upgrades.To = append(upgrades.To, upgradeEdge(history))
 	}
-	testName, err := utils.ExtractTestNameFromURL(prowurl)
+	for _, history := range info.UpgradesFrom {
+		upgrades.From = append(upgrades.From, upgradeEdge(history))
+	}
+	// Newest releases first
+	slices.SortStableFunc(upgrades.To, func(a, b api.UpgradeEdge) int { return releasename.Compare(b.From, a.From) })
+	slices.SortStableFunc(upgrades.From, func(a, b api.UpgradeEdge) int { return releasename.Compare(b.To, a.To) })
+	return upgrades, nil
+}
+
+// GetUpgradeEdge gets the results of the upgrade tests from a release to a release of the stream
+func (r *releaseControllerCli) GetUpgradeEdge(ctx context.Context, releasecontroller, stream, from, to string) (*api.UpgradeEdge, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, to)
 	if err != nil {
-		return "", fmt.Errorf("error fetching test name: %w", err)
+		return nil, err
 	}
-	if !strings.HasPrefix(stepName, testName+"-") {
-		return "", fmt.Errorf("stepName does not start with testName prefix")
+	for _, history := range info.UpgradesTo {
+		if history.From == from {
+			edge := upgradeEdge(history)
+			return &edge, nil
+		}
 	}
-	stepFolder := strings.TrimPrefix(stepName, testName+"-")
-	artifactURL := fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/%s/build-log.txt", logsPath, testName, stepFolder)
-	testLogs, err := utils.FetchURL(artifactURL)
+	return nil, fmt.Errorf("no upgrade from %s to %s recorded", from, to)
+}
+
+// upgradeEdge summarizes the upgrade history between two releases
+func upgradeEdge(history api.UpgradeHistory) api.UpgradeEdge {
+	edge := api.UpgradeEdge{
+		From:    history.From,
+		To:      history.To,
+		Success: history.Success,
+		Failure: history.Failure,
+		Total:   history.Total,
+		Runs:    []api.UpgradeRun{},
+	}
+	if finished := history.Success + history.Failure; finished > 0 {
+		edge.SuccessRate = 100 * float64(history.Success) / float64(finished)
+	}
+	for _, result := range history.History {
+		edge.Runs = append(edge.Runs, api.UpgradeRun{State: result.State, URL: result.URL})
+	}
+	sort.Slice(edge.Runs, func(i, j int) bool { return edge.Runs[i].URL < edge.Runs[j].URL })
+	return edge
+}
+
+// ListTestFailuresForRelease gets the failing tests for the particular job
+func (r *releaseControllerCli) ListTestFailuresForRelease(ctx context.Context, prowurl string) (*api.TestResults, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, r.config)
+	if err != nil {
+		return nil, fmt.Errorf("error opening job artifacts: %w", err)
+	}
+	testName, stepFolder, err := r.failedTestStep(ctx, src)
 	if err != nil {
-		return "", fmt.Errorf("error fetching test logs: %w", err)
+		return nil, err
 	}
-	testLog, err := utils.ExtractFailingTestsBlock(testLogs)
+	artifactPath := fmt.Sprintf("artifacts/%s/%s/build-log.txt", testName, stepFolder)
+	testLogs, err := src.Fetch(ctx, artifactPath)
 	if err != nil {
-		//no failing tests found
-		return fmt.Sprintf("No failing tests found for %s in job", testName), nil
+		return nil, fmt.Errorf("error fetching test logs: %w", err)
+	}
+	results := &api.TestResults{Job: src.JobName(), Test: testName, Step: stepFolder, Tests: []string{}}
+	if tests, lines, err := utils.ExtractFailingTests(testLogs); err == nil {
+		results.Tests, results.Lines = tests, lines
 	}
-	return testLog, nil
+	return results, nil
 }
 
 // GetFlakyTestsForRelease gets the flaky tests for the particular job
-func (r *releaseControllerCli) GetFlakyTestsForRelease(prowurl string) (string, error) {
-	logsPath := utils.ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
+func (r *releaseControllerCli) GetFlakyTestsForRelease(ctx context.Context, prowurl string) (*api.TestResults, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, r.config)
+	if err != nil {
+		return nil, fmt.Errorf("error opening job artifacts: %w", err)
 	}
-	joburl := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", logsPath)
-	data, err := utils.FetchURL(joburl)
+	testName, stepFolder, err := r.failedTestStep(ctx, src)
 	if err != nil {
-		return "", fmt.Errorf("error fetching job log: %w", err)
+		return nil, err
 	}
-	stepName, err := utils.ExtractStepName(data)
+	artifactPath := fmt.Sprintf("artifacts/%s/%s/build-log.txt", testName, stepFolder)
+	testLogs, err := src.Fetch(ctx, artifactPath)
 	if err != nil {
-		return "", fmt.Errorf("Could not find failure step - not a test run", err)
+		return nil, fmt.Errorf("error fetching test logs: %w", err)
 	}
-	testName, err := utils.ExtractTestNameFromURL(prowurl)
+	results := &api.TestResults{Job: src.JobName(), Test: testName, Step: stepFolder, Tests: []string{}}
+	if tests, lines, err := utils.ExtractFlakyTests(testLogs); err == nil {
+		results.Tests, results.Lines = tests, lines
+	}
+	return results, nil
+}
+
+func (r *releaseControllerCli) GetRiskAnalysisData(ctx context.Context, prowurl string) (*api.RiskAnalysis, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, r.config)
 	if err != nil {
-		return "", fmt.Errorf("error fetching test name: %w", err)
+		return nil, fmt.Errorf("error opening job artifacts: %w", err)
 	}
-	if !strings.HasPrefix(stepName, testName+"-") {
-		return "", fmt.Errorf("stepName does not start with testName prefix")
+	testName, stepFolder, err := r.failedTestStep(ctx, src)
+	if err != nil {
+		return nil, err
 	}
-	stepFolder := strings.TrimPrefix(stepName, testName+"-")
-	artifactURL := fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/%s/build-log.txt", logsPath, testName, stepFolder)
-	testLogs, err := utils.FetchURL(artifactURL)
+	artifactPath := fmt.Sprintf("artifacts/%s/%s/artifacts/junit/risk-analysis.json", testName, stepFolder)
+	riskAnalysisLogs, err := src.Fetch(ctx, artifactPath)
 	if err != nil {
-		return "", fmt.Errorf("error fetching test logs: %w", err)
+		return nil, fmt.Errorf("risk analysis logs not present: %w", err)
 	}
-	testLog, _ := utils.ExtractFlakyTestsBlock(testLogs)
-	return testLog, nil
+	if !json.Valid([]byte(riskAnalysisLogs)) {
+		return nil, fmt.Errorf("invalid risk analysis data in %s", artifactPath)
+	}
+	return &api.RiskAnalysis{Job: src.JobName(), Data: json.RawMessage(riskAnalysisLogs)}, nil
 }
 
-func (r *releaseControllerCli) GetRiskAnalysisData(prowurl string) (string, error) {
-	logsPath := utils.ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
-	}
-	joburl := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", logsPath)
-	data, err := utils.FetchURL(joburl)
+func (r *releaseControllerCli) GetSpyglassDataRelevantToTestFailure(ctx context.Context, prowurl string, testName string) ([]api.SpyglassEvent, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, r.config)
 	if err != nil {
-		return "", fmt.Errorf("error fetching job log: %w", err)
+		return nil, fmt.Errorf("error opening job artifacts: %w", err)
 	}
-	stepName, err := utils.ExtractStepName(data)
+	testFolderName, stepFolder, err := r.failedTestStep(ctx, src)
 	if err != nil {
-		return "", fmt.Errorf("Could not find failure step - not a test run", err)
+		return nil, err
 	}
-	testName, err := utils.ExtractTestNameFromURL(prowurl)
+	spyglassFiles, err := utils.GetSpyglassFileNames(ctx, src, testFolderName, stepFolder)
 	if err != nil {
-		return "", fmt.Errorf("error fetching test name: %w", err)
+		return nil, fmt.Errorf("failed to get spyglass file names: %w", err)
 	}
-	if !strings.HasPrefix(stepName, testName+"-") {
-		return "", fmt.Errorf("stepName does not start with testName prefix")
+	if len(spyglassFiles) == 0 {
+		return nil, fmt.Errorf("no spyglass files found for %s", stepFolder)
 	}
-	stepFolder := strings.TrimPrefix(stepName, testName+"-")
-	artifactURL := fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/%s/artifacts/junit/risk-analysis.json", logsPath, testName, stepFolder)
-	riskAnalysisLogs, err := utils.FetchURL(artifactURL)
-	if err != nil {
-		return "", fmt.Errorf("risk analysis logs not present: %w", err)
+	events := []api.SpyglassEvent{}
+	for _, spyglassFileName := range spyglassFiles {
+		artifactPath := fmt.Sprintf("artifacts/%s/%s/artifacts/junit/%s", testFolderName, stepFolder, strings.TrimPrefix(spyglassFileName, " "))
+		fileEvents, err := utils.GetSpyglassDataRelevantToTestFailure(ctx, src, artifactPath, testName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get error and warning events: %w", err)
+		}
+		events = append(events, fileEvents...)
 	}
-	return riskAnalysisLogs, nil
+	return events, nil
 }
 
-func (r *releaseControllerCli) GetSpyglassDataRelevantToTestFailure(prowurl string, testName string) (string, error) {
-	logsPath := utils.ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
-	}
-	joburl := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", logsPath)
-	data, err := utils.FetchURL(joburl)
+// failedTestStep finds the step which failed in the top-level build log of the job, returning the
+// name of the CI test and the folder of the step in the artifacts of the test
+func (r *releaseControllerCli) failedTestStep(ctx context.Context, src artifacts.ArtifactSource) (string, string, error) {
+	data, err := src.Fetch(ctx, "build-log.txt")
 	if err != nil {
-		return "", fmt.Errorf("error fetching job log: %w", err)
+		return "", "", fmt.Errorf("error fetching job log: %w", err)
 	}
 	stepName, err := utils.ExtractStepName(data)
 	if err != nil {
-		return "", fmt.Errorf("Could not find failure step - not a test run", err)
+		return "", "", fmt.Errorf("could not find failure step - not a test run: %w", err)
 	}
-	testFolderName, err := utils.ExtractTestNameFromURL(prowurl)
+	testName, err := utils.ExtractTestNameFromURL(src.JobName())
 	if err != nil {
-		return "", fmt.Errorf("error fetching test folder name: %w", err)
+		return "", "", fmt.Errorf("error fetching test name: %w", err)
 	}
-	if !strings.HasPrefix(stepName, testFolderName+"-") {
-		return "", fmt.Errorf("stepName does not start with testName prefix")
+	if !strings.HasPrefix(stepName, testName+"-") {
+		return "", "", fmt.Errorf("stepName does not start with testName prefix")
 	}
-	stepFolder := strings.TrimPrefix(stepName, testFolderName+"-")
-	var errorEvents string
-	spyglassFiles, err := utils.GetSpyglassFileNames(logsPath, testFolderName, stepFolder)
+	return testName, strings.TrimPrefix(stepName, testName+"-"), nil
+}
+
+// GetReleaseInfo gets the verification results, upgrades and changelog of a release
+func (r *releaseControllerCli) GetReleaseInfo(ctx context.Context, releasecontroller, stream, tag string) (*api.APIReleaseInfo, error) {
+	return r.releaseInfo(ctx, releasecontroller, stream, tag)
+}
+
+// releaseInfo fetches the verification results and changelog of a release
+func (r *releaseControllerCli) releaseInfo(ctx context.Context, releasecontroller, stream, tag string) (*api.APIReleaseInfo, error) {
+	baseURL, err := r.config.ReleaseControllerURL(releasecontroller)
 	if err != nil {
-		return "No spyglass files found", fmt.Errorf("failed to get spyglass file names: %w", err)
+		return nil, err
 	}
-	for _, spyglassFileName := range spyglassFiles {
-		artifactURL := fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/%s/artifacts/junit/%s", logsPath, testFolderName, stepFolder, strings.TrimPrefix(spyglassFileName, " "))
-		events, err := utils.GetSpyglassDataRelevantToTestFailure(artifactURL, testName)
-		if err != nil {
-			return "No data available", fmt.Errorf("failed to get error and warning events: %w", err)
-		}
-		errorEvents += events
+	data, err := utils.FetchJSONBytes(ctx, fmt.Sprintf("%s/api/v1/releasestream/%s/release/%s", baseURL, stream, tag))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release info: %w", err)
 	}
-	if errorEvents == "" {
-		if len(spyglassFiles) <= 0 {
-			return "No spyglass files??", nil
-		}
-		return "No error or warning events found in spyglass data" + spyglassFiles[0], nil
+	info, err := utils.ParseAPIReleaseInfo(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release info: %w", err)
 	}
-	return errorEvents, nil
+	return info, nil
 }
 
 // GetTopLevelBuildLog gets the top-level build log for a given Prow job URL
-func (r *releaseControllerCli) GetTopLevelBuildLog(prowurl string, LogCompactionThreshold string) (string, error) {
-	logsPath := utils.ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
+func (r *releaseControllerCli) GetTopLevelBuildLog(ctx context.Context, prowurl string, LogCompactionThreshold string) (*api.JobLog, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, r.config)
+	if err != nil {
+		return nil, fmt.Errorf("error opening job artifacts: %w", err)
 	}
-	joburl := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", logsPath)
-	data, err := utils.FetchURL(joburl)
+	data, err := src.Fetch(ctx, "build-log.txt")
 	if err != nil {
-		return "", fmt.Errorf("error fetching job log: %w", err)
+		return nil, fmt.Errorf("error fetching job log: %w", err)
 	}
 
-	var compactedLogs string
 	if strings.Contains(data, "e2e") {
-		var threshold float64
-		switch LogCompactionThreshold {
-		case "aggressive":
-			threshold = 0.5 // Aggressive compaction threshold
-		case "moderate":
-			threshold = 0.8 // Moderate compaction threshold
-		case "conservative":
-			threshold = 0.9 // Conservative compaction threshold
-		default:
-			threshold = 1.0 // Default threshold (only removes exact duplicates)
+		data, err = utils.CompactTestLogs(ctx, data, compactionThreshold(LogCompactionThreshold))
+		if err != nil {
+			return nil, fmt.Errorf("error compacting build log: %w", err)
 		}
-		compactedLogs = utils.CompactTestLogs(data, threshold)
-	} else {
-		compactedLogs = data
 	}
 
-	return compactedLogs, nil
+	return &api.JobLog{Job: src.JobName(), Path: "build-log.txt", Log: data}, nil
 }
 
 // AnalyzeJobFailuresForRelease gets the build log file for the particular job
-func (r *releaseControllerCli) AnalyzeJobFailuresForRelease(prowurl string, LogCompactionThreshold string) (string, error) {
-	logsPath := utils.ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
+func (r *releaseControllerCli) AnalyzeJobFailuresForRelease(ctx context.Context, prowurl string, LogCompactionThreshold string) (*api.JobLog, error) {
+	src, err := artifacts.NewArtifactSource(ctx, prowurl, r.config)
+	if err != nil {
+		return nil, fmt.Errorf("error opening job artifacts: %w", err)
 	}
-	joburl := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", logsPath)
-	data, err := utils.FetchURL(joburl)
+	data, err := src.Fetch(ctx, "build-log.txt")
 	if err != nil {
-		return "", fmt.Errorf("error fetching job log: %w", err)
+		return nil, fmt.Errorf("error fetching job log: %w", err)
 	}
 	stepName, err := utils.ExtractStepName(data)
 	if err != nil {
-		return data, nil
+		return &api.JobLog{Job: src.JobName(), Path: "build-log.txt", Log: data}, nil
 	}
 
-	var artifactURL string
+	var artifactPath string
 	switch stepName {
 	case "release-analysis-aggregator-openshift-release-analysis-aggregator":
-		artifactURL = fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/release-analysis-aggregator/openshift-release-analysis-aggregator/build-log.txt", logsPath)
-	case "release-payload-install-analysis-openshift-release-analysis-test-case-analysis":
-		artifactURL = fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/release-payload-install-analysis/openshift-release-analysis-test-case-analysis/build-log.txt", logsPath)
-		installAnalysisLogs, err := utils.FetchURL(artifactURL)
-		if err != nil {
-			return "", fmt.Errorf("error fetching test logs: %w", err)
-		}
-		artifactURL = strings.TrimSuffix(artifactURL, "build-log.txt") + "artifacts"
-		installAnalysisJobFailues, err := utils.FetchAggregateJobFailures(artifactURL, installAnalysisLogs)
-		if err != nil {
-			return "", fmt.Errorf("error fetching aggregate job failures: %w", err)
-		}
-		return installAnalysisJobFailues, nil
-	case "release-payload-overall-analysis-all-openshift-release-analysis-test-case-analysis":
-		artifactURL = fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/release-payload-overall-analysis-all/openshift-release-analysis-test-case-analysis/build-log.txt", logsPath)
-		overallAnalysisLogs, err := utils.FetchURL(artifactURL)
-		if err != nil {
-			return "", fmt.Errorf("error fetching test logs: %w", err)
-		}
-		artifactURL = strings.TrimSuffix(artifactURL, "build-log.txt") + "artifacts"
-		overallAnalysisJobFailues, err := utils.FetchAggregateJobFailures(artifactURL, overallAnalysisLogs)
-		if err != nil {
-			return "", fmt.Errorf("error fetching aggregate job failures: %w", err)
-		}
-		return overallAnalysisJobFailues, nil
-	case "release-payload-upgrade-analysis-all-openshift-release-analysis-test-case-analysis":
-		artifactURL = fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/release-payload-upgrade-analysis-all/openshift-release-analysis-test-case-analysis/build-log.txt", logsPath)
-		upgradeAnalysisLogs, err := utils.FetchURL(artifactURL)
+		artifactPath = "artifacts/release-analysis-aggregator/openshift-release-analysis-aggregator/build-log.txt"
+	case "release-payload-install-analysis-openshift-release-analysis-test-case-analysis",
+		"release-payload-overall-analysis-all-openshift-release-analysis-test-case-analysis",
+		"release-payload-upgrade-analysis-all-openshift-release-analysis-test-case-analysis":
+		// The test case analysis steps aggregate the failures of the jobs they analyzed
+		testName := strings.TrimSuffix(stepName, "-openshift-release-analysis-test-case-analysis")
+		artifactPath = fmt.Sprintf("artifacts/%s/openshift-release-analysis-test-case-analysis/build-log.txt", testName)
+		analysisLogs, err := src.Fetch(ctx, artifactPath)
 		if err != nil {
-			return "", fmt.Errorf("error fetching test logs: %w", err)
+			return nil, fmt.Errorf("error fetching test logs: %w", err)
 		}
-		artifactURL = strings.TrimSuffix(artifactURL, "build-log.txt") + "artifacts"
-		upgradeAnalysisJobFailues, err := utils.FetchAggregateJobFailures(artifactURL, upgradeAnalysisLogs)
+		artifactPath = strings.TrimSuffix(artifactPath, "build-log.txt") + "artifacts"
+		analysisJobFailures, err := utils.FetchAggregateJobFailures(ctx, src, artifactPath, analysisLogs)
 		if err != nil {
-			return "", fmt.Errorf("error fetching aggregate job failures: %w", err)
+			return nil, fmt.Errorf("error fetching aggregate job failures: %w", err)
 		}
-		return upgradeAnalysisJobFailues, nil
+		return &api.JobLog{Job: src.JobName(), Step: stepName, Path: artifactPath, Log: analysisJobFailures}, nil
 	default:
-		testName, err := utils.ExtractTestNameFromURL(prowurl)
+		testName, err := utils.ExtractTestNameFromURL(src.JobName())
 		if err != nil {
-			return "", fmt.Errorf("error fetching test name: %w", err)
+			return nil, fmt.Errorf("error fetching test name: %w", err)
 		}
 
 		if !strings.HasPrefix(stepName, testName+"-") {
-			return "", fmt.Errorf("stepName does not start with testName prefix")
+			return nil, fmt.Errorf("stepName does not start with testName prefix")
 		}
 		stepFolder := strings.TrimPrefix(stepName, testName+"-")
-		artifactURL = fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/%s/build-log.txt", logsPath, testName, stepFolder)
+		artifactPath = fmt.Sprintf("artifacts/%s/%s/build-log.txt", testName, stepFolder)
 	}
 
-	testLogs, err := utils.FetchURL(artifactURL)
+	testLogs, err := src.Fetch(ctx, artifactPath)
 	if err != nil {
-		return "", fmt.Errorf("error fetching test logs: %w", err)
+		return nil, fmt.Errorf("error fetching test logs: %w", err)
 	}
 	if strings.Contains(stepName, "e2e") {
-		var threshold float64
-		switch LogCompactionThreshold {
-		case "aggressive":
-			threshold = 0.5 // Aggressive compaction threshold
-		case "moderate":
-			threshold = 0.8 // Moderate compaction threshold
-		case "conservative":
-			threshold = 0.9 // Conservative compaction threshold
-		default:
-			threshold = 1.0 // Default threshold (only removes exact duplicates)
-		}
 		// If the step is an e2e test, we want to compact the logs
-		testLogs = utils.CompactTestLogs(testLogs, threshold)
+		testLogs, err = utils.CompactTestLogs(ctx, testLogs, compactionThreshold(LogCompactionThreshold))
+		if err != nil {
+			return nil, fmt.Errorf("error compacting test logs: %w", err)
+		}
 	}
 	monitorLogs, err := utils.ExtractMonitorTestFailures(testLogs)
 	if err == nil {
 		// If monitor logs are found, return them as there were no test failures
-		return monitorLogs, nil
+		testLogs = monitorLogs
 	}
-	return testLogs, nil
+	return &api.JobLog{Job: src.JobName(), Step: stepName, Path: artifactPath, Log: testLogs}, nil
 }
 
-// List issues which are features from updated images commits - excludes OCPBUGS/CVEs
-func (r *releaseControllerCli) ListFeaturesFromUpdatedImagesCommits(releasecontroller, stream, tag string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/release/%s", releasecontroller, stream, tag))
-	if err != nil {
-		return "", fmt.Errorf("error fetching release info: %w", err)
+// compactionThreshold returns the similarity threshold above which log lines are compacted
+func compactionThreshold(level string) float64 {
+	switch level {
+	case "aggressive":
+		return 0.5 // Aggressive compaction threshold
+	case "moderate":
+		return 0.8 // Moderate compaction threshold
+	case "conservative":
+		return 0.9 // Conservative compaction threshold
+	default:
+		return 1.0 // Default threshold (only removes exact duplicates)
 	}
-	info, err := utils.ParseAPIReleaseInfo(data)
+}
+
+// List issues which are features from updated images commits - excludes OCPBUGS/CVEs
+func (r *releaseControllerCli) ListFeaturesFromUpdatedImagesCommits(ctx context.Context, releasecontroller, stream, tag string) ([]api.Issue, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
 	if err != nil {
-		return "", fmt.Errorf("error parsing release info: %w", err)
-	}
-	var components []string
-	for _, component := range info.ChangeLogJson.UpdatedImages {
-		for _, commit := range component.Commits {
-			if len(commit.Issues) > 0 {
-				for issue, url := range commit.Issues {
-					if strings.HasPrefix(issue, "OCPBUGS-") || strings.HasPrefix(issue, "CVE-") {
-						continue // Skip OCPBUGS and CVEs
-					}
-					// Add the issue to the components list
-					// Format: "issue: url (component name)"
-					components = append(components, fmt.Sprintf("%s: %s", issue, url))
-				}
-			}
-		}
+		return nil, err
 	}
-	if len(components) == 0 {
-		return "No issues found in updated images commits", nil
-	}
-	return strings.Join(components, "\n"), nil
+	return updatedImagesIssues(info, func(commit api.CommitInfo, issue string) bool {
+		// Skip OCPBUGS and CVEs
+		return !strings.HasPrefix(issue, "OCPBUGS-") && !strings.HasPrefix(issue, "CVE-")
+	}), nil
 }
 
 // List issues which are bugs from updated images commits
-func (r *releaseControllerCli) ListBugsFromUpdatedImagesCommits(releasecontroller, stream, tag string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/release/%s", releasecontroller, stream, tag))
-	if err != nil {
-		return "", fmt.Errorf("error fetching release info: %w", err)
-	}
-	info, err := utils.ParseAPIReleaseInfo(data)
+func (r *releaseControllerCli) ListBugsFromUpdatedImagesCommits(ctx context.Context, releasecontroller, stream, tag string) ([]api.Issue, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
 	if err != nil {
-		return "", fmt.Errorf("error parsing release info: %w", err)
-	}
-	var components []string
-	for _, component := range info.ChangeLogJson.UpdatedImages {
-		for _, commit := range component.Commits {
-			if len(commit.Issues) > 0 {
-				for issue, url := range commit.Issues {
-					if strings.HasPrefix(issue, "OCPBUGS-") {
-						// Add the issue to the components list
-						// Format: "issue: url (component name)"
-						components = append(components, fmt.Sprintf("%s: %s", issue, url))
-					}
-				}
-			}
-		}
-	}
-	if len(components) == 0 {
-		return "No issues found in updated images commits", nil
+		return nil, err
 	}
-	return strings.Join(components, "\n"), nil
+	return updatedImagesIssues(info, func(commit api.CommitInfo, issue string) bool {
+		return strings.HasPrefix(issue, "OCPBUGS-")
+	}), nil
 }
 
 // List issues which are CVEs from updated images commits
-func (r *releaseControllerCli) ListCVEsFromUpdatedImagesCommits(releasecontroller, stream, tag string) (string, error) {
-	data, err := utils.FetchJSONBytes(fmt.Sprintf("https://%s/api/v1/releasestream/%s/release/%s", releasecontroller, stream, tag))
+func (r *releaseControllerCli) ListCVEsFromUpdatedImagesCommits(ctx context.Context, releasecontroller, stream, tag string) ([]api.Issue, error) {
+	info, err := r.releaseInfo(ctx, releasecontroller, stream, tag)
 	if err != nil {
-		return "", fmt.Errorf("error fetching release info: %w", err)
+		return nil, err
 	}
-	info, err := utils.ParseAPIReleaseInfo(data)
-	if err != nil {
-		return "", fmt.Errorf("error parsing release info: %w", err)
-	}
-	var components []string
+	return updatedImagesIssues(info, func(commit api.CommitInfo, issue string) bool {
+		// Only consider commits with CVE issues
+		return strings.Contains(commit.Subject, "CVE")
+	}), nil
+}
+
+// updatedImagesIssues returns the issues referenced by the updated images commits of a
+// release which match the filter, sorted by ID
+func updatedImagesIssues(info *api.APIReleaseInfo, match func(commit api.CommitInfo, issue string) bool) []api.Issue {
+	urls := map[string]string{}
 	for _, component := range info.ChangeLogJson.UpdatedImages {
 		for _, commit := range component.Commits {
-			if len(commit.Issues) > 0 && strings.Contains(commit.Subject, "CVE") {
-				// Only consider commits with CVE issues
-				for issue, url := range commit.Issues {
-					// Add the issue to the components list
-					// Format: "issue: url (component name)"
-					components = append(components, fmt.Sprintf("%s: %s", issue, url))
+			for issue, url := range commit.Issues {
+				if match(commit, issue) {
+					urls[issue] = url
 				}
 			}
 		}
 	}
-	if len(components) == 0 {
-		return "No issues found in updated images commits", nil
+	issues := []api.Issue{}
+	for issue, url := range urls {
+		issues = append(issues, api.Issue{ID: issue, URL: url})
 	}
-	return strings.Join(components, "\n"), nil
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+	return issues
 }
 
-func newReleaseControllerCli() *releaseControllerCli {
+func newReleaseControllerCli(cfg *config.Config) *releaseControllerCli {
 	return &releaseControllerCli{
-		releaseControllers: []string{OKDReleaseController, OCPReleaseController, MultiReleaseController, ARM64ReleaseController, PPC64LEReleaseController, S390XReleaseController},
+		config: cfg,
 	}
 }
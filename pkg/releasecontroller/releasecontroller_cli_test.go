@@ -0,0 +1,284 @@
+package releasecontroller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/config"
+)
+
+func TestCompareReleases(t *testing.T) {
+	shared := api.CommitInfo{
+		Subject: "OCPBUGS-1: fix the installer",
+		PullURL: "https://github.com/openshift/installer/pull/1",
+		Bugs:    map[string]string{"OCPBUGS-1": "https://issues.redhat.com/browse/OCPBUGS-1"},
+	}
+	comparison := compareReleases("4.19.3", "4.19.7", &api.ChangeLog{
+		Components: []api.ChangeLogComponentInfo{
+			{Name: "Kubernetes", From: "1.32.3", Version: "1.32.5"},
+			{Name: "Red Hat Enterprise Linux CoreOS", From: "9.6", Version: "9.6"},
+		},
+		NewImages:     []api.ChangeLogImageInfo{{Name: "new-operator"}},
+		RemovedImages: []api.ChangeLogImageInfo{{Name: "old-operator"}},
+		UpdatedImages: []api.ChangeLogImageInfo{
+			{Name: "installer", Commits: []api.CommitInfo{shared, {
+				Subject: "CVE-2025-1234: bump golang.org/x/net",
+				PullURL: "https://github.com/openshift/installer/pull/2",
+				Issues:  map[string]string{"CVE-2025-1234": "https://access.redhat.com/security/cve/CVE-2025-1234", "OCPBUGS-2": "https://issues.redhat.com/browse/OCPBUGS-2"},
+			}}},
+			{Name: "installer-artifacts", Commits: []api.CommitInfo{shared}},
+		},
+	})
+	if len(comparison.Components) != 1 || comparison.Components[0].Name != "Kubernetes" || comparison.Components[0].From != "1.32.3" {
+		t.Errorf("expected only the Kubernetes version change, got %+v", comparison.Components)
+	}
+	if !slices.Equal(comparison.NewImages, []string{"new-operator"}) || !slices.Equal(comparison.RemovedImages, []string{"old-operator"}) {
+		t.Errorf("unexpected images %v %v", comparison.NewImages, comparison.RemovedImages)
+	}
+	if len(comparison.UpdatedImages) != 2 || comparison.UpdatedImages[0].Commits != 2 {
+		t.Errorf("unexpected updated images %+v", comparison.UpdatedImages)
+	}
+	if len(comparison.Commits) != 2 {
+		t.Errorf("expected the commit shared by two images once, got %+v", comparison.Commits)
+	} else if !slices.Equal(comparison.Commits[1].Issues, []string{"CVE-2025-1234", "OCPBUGS-2"}) {
+		t.Errorf("unexpected commit issues %v", comparison.Commits[1].Issues)
+	}
+	var issues []string
+	for _, issue := range comparison.Issues {
+		issues = append(issues, issue.ID)
+	}
+	if !slices.Equal(issues, []string{"CVE-2025-1234", "OCPBUGS-1", "OCPBUGS-2"}) {
+		t.Errorf("unexpected issues %v", issues)
+	}
+}
+
+func TestUpgradeEdge(t *testing.T) {
+	edge := upgradeEdge(api.UpgradeHistory{
+		From: "4.18.12", To: "4.19.0-0.nightly-2025-05-02-000000",
+		Success: 3, Failure: 1, Total: 5,
+		History: map[string]api.UpgradeResult{
+			"b": {State: "Failed", URL: "https://prow.ci.openshift.org/view/gs/test-platform-results/logs/upgrade/2"},
+			"a": {State: "Succeeded", URL: "https://prow.ci.openshift.org/view/gs/test-platform-results/logs/upgrade/1"},
+		},
+	})
+	if edge.SuccessRate != 75 {
+		t.Errorf("expected the pending run to be left out of the success rate, got %f", edge.SuccessRate)
+	}
+	if len(edge.Runs) != 2 || edge.Runs[0].State != "Succeeded" {
+		t.Errorf("unexpected runs %+v", edge.Runs)
+	}
+}
+
+func TestPayloadStatus(t *testing.T) {
+	started := time.Date(2025, 5, 2, 10, 0, 0, 0, time.UTC)
+	info := &api.APIReleaseInfo{
+		Name:  "4.19.0-0.nightly-2025-05-02-000000",
+		Phase: "Ready",
+		Results: &api.VerificationJobsSummary{
+			BlockingJobs: api.VerificationStatusMap{
+				"aws-serial": {State: "Succeeded", Retries: 1},
+				"aws-ovn":    {State: "Pending", TransitionTime: &started},
+			},
+			InformingJobs: api.VerificationStatusMap{
+				"metal-ipi": {State: "Failed", Retries: 2},
+			},
+		},
+	}
+	status := payloadStatus(info, started.Add(90*time.Minute+20*time.Second))
+	var names []string
+	for _, job := range status.Jobs {
+		names = append(names, job.Name)
+	}
+	if !slices.Equal(names, []string{"metal-ipi", "aws-ovn", "aws-serial"}) {
+		t.Errorf("unexpected job order %v", names)
+	}
+	if status.Jobs[1].RunningFor != "1h30m0s" || status.Jobs[0].RunningFor != "" {
+		t.Errorf("unexpected running times %q %q", status.Jobs[1].RunningFor, status.Jobs[0].RunningFor)
+	}
+	if !status.CanBeAccepted {
+		t.Errorf("expected a failed informing job not to prevent acceptance: %s", status.Verdict)
+	}
+	var outcomes []api.JobOutcome
+	for _, job := range status.Jobs {
+		outcomes = append(outcomes, job.Outcome)
+	}
+	if !slices.Equal(outcomes, []api.JobOutcome{api.JobOutcomeExhaustedRetries, api.JobOutcomePending, api.JobOutcomePassedAfterRetry}) {
+		t.Errorf("unexpected outcomes %v", outcomes)
+	}
+
+	info.Results.BlockingJobs["aws-serial"].State = "Failed"
+	if status := payloadStatus(info, time.Now()); status.CanBeAccepted {
+		t.Errorf("expected a failed blocking job to prevent acceptance: %s", status.Verdict)
+	}
+}
+
+func TestStreamHealth(t *testing.T) {
+	tags := []api.Tag{
+		{Name: "4.19.0-0.nightly-2025-05-05-000000", Phase: "Ready"},
+		{Name: "4.19.0-0.nightly-2025-05-04-000000", Phase: "Rejected"},
+		{Name: "4.19.0-0.nightly-2025-05-03-000000", Phase: "Accepted"},
+		{Name: "4.19.0-0.nightly-2025-05-02-000000", Phase: "Rejected"},
+		{Name: "4.19.0-0.nightly-2025-05-01-000000", Phase: "Rejected"},
+	}
+	result := func(aws, metal string) *api.APIReleaseInfo {
+		return &api.APIReleaseInfo{Results: &api.VerificationJobsSummary{
+			BlockingJobs:  api.VerificationStatusMap{"aws": {State: aws}},
+			InformingJobs: api.VerificationStatusMap{"metal": {State: metal}},
+		}}
+	}
+	health := streamHealth("4.19.0-0.nightly", &payloads{tags: tags, infos: []*api.APIReleaseInfo{
+		result("Pending", "Pending"),
+		result("Failed", "Succeeded"),
+		nil,
+		result("Failed", "Failed"),
+		result("Succeeded", "Failed"),
+	}, errs: []error{nil, nil, errors.New("not found"), nil, nil}}, nil, time.Date(2025, 5, 5, 12, 0, 0, 0, time.UTC))
+	if health.Accepted != 1 || health.Rejected != 3 || health.Other != 1 || health.AcceptanceRate != 25 {
+		t.Errorf("unexpected counts %+v", health)
+	}
+	if health.LongestRejectionStreak != 2 || health.CurrentRejectionStreak != 1 {
+		t.Errorf("unexpected rejection streaks %d %d", health.LongestRejectionStreak, health.CurrentRejectionStreak)
+	}
+	if health.LastAccepted != tags[2].Name || health.LastAcceptedAt == nil || health.LastAcceptedAt.Day() != 3 || health.SinceLastAccepted != "60h0m0s" {
+		t.Errorf("unexpected last accepted %s %v %s", health.LastAccepted, health.LastAcceptedAt, health.SinceLastAccepted)
+	}
+	if !slices.Equal(health.Unavailable, []api.UnavailablePayload{{Name: tags[2].Name, Error: "not found"}}) {
+		t.Errorf("unexpected unavailable payloads %v", health.Unavailable)
+	}
+	if len(health.Jobs) != 2 || health.Jobs[0].Name != "aws" || health.Jobs[0].Runs != 3 || health.Jobs[0].Passed != 1 || health.Jobs[1].PassRate != 100.0/3 {
+		t.Errorf("unexpected job pass rates %+v", health.Jobs)
+	}
+
+	created := time.Date(2025, 4, 20, 0, 0, 0, 0, time.UTC)
+	health = streamHealth("4.19.0-0.nightly", &payloads{tags: tags, infos: []*api.APIReleaseInfo{nil, nil}}, &api.APIReleaseInfo{ChangeLogJson: api.ChangeLog{To: api.ChangeLogReleaseInfo{Created: created}}}, created.Add(time.Hour))
+	if health.Accepted != 0 || health.LastAccepted != tags[2].Name || health.LastAcceptedAt == nil || !health.LastAcceptedAt.Equal(created) || health.SinceLastAccepted != "1h0m0s" {
+		t.Errorf("expected the last accepted payload to be found past the payloads looked at, got %s %v", health.LastAccepted, health.LastAcceptedAt)
+	}
+}
+
+func TestJobMatrix(t *testing.T) {
+	tags := []api.Tag{
+		{Name: "4.19.0-0.nightly-2025-05-04-000000", Phase: "Ready"},
+		{Name: "4.19.0-0.nightly-2025-05-03-000000", Phase: "Rejected"},
+		{Name: "4.19.0-0.nightly-2025-05-02-000000", Phase: "Rejected"},
+		{Name: "4.19.0-0.nightly-2025-05-01-000000", Phase: "Accepted"},
+	}
+	matrix := jobMatrix("4.19.0-0.nightly", &payloads{tags: tags, infos: []*api.APIReleaseInfo{
+		{Results: &api.VerificationJobsSummary{
+			BlockingJobs:  api.VerificationStatusMap{"aws": {State: "Pending"}},
+			InformingJobs: api.VerificationStatusMap{"metal": {State: "Succeeded"}},
+		}},
+		{Results: &api.VerificationJobsSummary{
+			BlockingJobs: api.VerificationStatusMap{"aws": {State: "Failed", URL: "https://prow.ci.openshift.org/view/gs/test-platform-results/logs/aws/3", Retries: 2}},
+		}},
+		{Results: &api.VerificationJobsSummary{
+			BlockingJobs: api.VerificationStatusMap{"aws": {State: "Failed"}},
+		}},
+		nil,
+	}})
+	if len(matrix.Payloads) != 4 || matrix.Payloads[3].Available || !matrix.Payloads[0].Available {
+		t.Errorf("unexpected payloads %+v", matrix.Payloads)
+	}
+	if len(matrix.Jobs) != 2 || matrix.Jobs[0].Name != "aws" || matrix.Jobs[1].Name != "metal" {
+		t.Fatalf("unexpected jobs %+v", matrix.Jobs)
+	}
+	aws := matrix.Jobs[0]
+	if aws.Cells[1].URL == "" || aws.Cells[1].Retries != 2 || aws.Cells[3].State != "" {
+		t.Errorf("unexpected cells %+v", aws.Cells)
+	}
+	if aws.FailingSince != tags[2].Name || matrix.Jobs[1].FailingSince != "" {
+		t.Errorf("unexpected failing since %q %q", aws.FailingSince, matrix.Jobs[1].FailingSince)
+	}
+}
+
+func TestBisectJob(t *testing.T) {
+	tags := []api.Tag{
+		{Name: "4.19.0-0.nightly-2025-05-04-000000"},
+		{Name: "4.19.0-0.nightly-2025-05-03-000000"},
+		{Name: "4.19.0-0.nightly-2025-05-02-000000"},
+		{Name: "4.19.0-0.nightly-2025-05-01-000000"},
+	}
+	info := func(state string) *api.APIReleaseInfo {
+		return &api.APIReleaseInfo{Results: &api.VerificationJobsSummary{
+			BlockingJobs: api.VerificationStatusMap{"aws": {State: state, URL: "https://prow.ci.openshift.org/view/gs/test-platform-results/logs/aws/" + state}},
+		}}
+	}
+	bisection, err := bisectJob("4.19.0-0.nightly", "aws", &payloads{tags: tags, infos: []*api.APIReleaseInfo{info("Failed"), info("Pending"), info("Failed"), info("Succeeded")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bisection.FirstFailed != tags[2].Name || bisection.LastPassed != tags[3].Name || bisection.Failures != 2 || bisection.Kind != api.JobKindBlocking {
+		t.Errorf("unexpected bisection %+v", bisection)
+	}
+
+	bisection, err = bisectJob("4.19.0-0.nightly", "aws", &payloads{tags: tags, infos: []*api.APIReleaseInfo{info("Failed"), info("Failed"), nil, nil}})
+	if err != nil || bisection.FirstFailed != tags[1].Name || bisection.LastPassed != "" {
+		t.Errorf("expected a streak going past the payloads looked at, got %+v %v", bisection, err)
+	}
+
+	if _, err := bisectJob("4.19.0-0.nightly", "aws", &payloads{tags: tags, infos: []*api.APIReleaseInfo{info("Succeeded"), info("Failed"), nil, nil}}); err == nil {
+		t.Errorf("expected an error for a job which is not failing")
+	}
+	if _, err := bisectJob("4.19.0-0.nightly", "gcp", &payloads{tags: tags, infos: []*api.APIReleaseInfo{info("Failed"), nil, nil, nil}}); err == nil {
+		t.Errorf("expected an error for a job which did not run")
+	}
+}
+
+func TestRecentReleaseInfosUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/tags") {
+			_, _ = w.Write([]byte(`{"name": "4.19.0-0.nightly", "tags": [{"name": "4.19.0-0.nightly-2025-05-02-000000", "phase": "Rejected"}, {"name": "4.19.0-0.nightly-2025-05-01-000000", "phase": "Accepted"}]}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	cfg := config.Default()
+	cfg.ReleaseControllers = []config.ReleaseController{{Name: "test", URL: server.URL}}
+	rc := &releaseControllerCli{config: cfg}
+	if _, err := rc.GetStreamHealth(context.Background(), "test", "4.19.0-0.nightly", 2); err == nil {
+		t.Errorf("expected an error when the release info of every payload is unavailable")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := rc.GetJobMatrix(ctx, "test", "4.19.0-0.nightly", 2); err == nil {
+		t.Errorf("expected an error when the context is cancelled")
+	}
+}
+
+func TestGetUpgradeEdgeNotRecorded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name": "4.19.1", "upgradesTo": [{"From": "4.19.0", "To": "4.19.1", "Success": 1, "Total": 1}]}`))
+	}))
+	defer server.Close()
+	cfg := config.Default()
+	cfg.ReleaseControllers = []config.ReleaseController{{Name: "test", URL: server.URL}}
+	rc := &releaseControllerCli{config: cfg}
+	if edge, err := rc.GetUpgradeEdge(context.Background(), "test", "4-stable", "4.19.0", "4.19.1"); err != nil || edge.Success != 1 {
+		t.Errorf("unexpected edge %+v: %v", edge, err)
+	}
+	if _, err := rc.GetUpgradeEdge(context.Background(), "test", "4-stable", "4.18.0", "4.19.1"); err == nil || !strings.Contains(err.Error(), "no upgrade from 4.18.0 to 4.19.1 recorded") {
+		t.Errorf("expected an error for an upgrade never recorded, got %v", err)
+	}
+}
+
+func TestListFailedJobsSkipsNullStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name": "4.19.1", "results": {"blockingJobs": {"aws": null, "gcp": {"state": "Failed", "url": "https://prow/gcp"}}, "informingJobs": {"metal": null}}}`))
+	}))
+	defer server.Close()
+	cfg := config.Default()
+	cfg.ReleaseControllers = []config.ReleaseController{{Name: "test", URL: server.URL}}
+	rc := &releaseControllerCli{config: cfg}
+	jobs, err := rc.ListFailedJobsInRelease(context.Background(), "test", "4-stable", "4.19.1")
+	if err != nil || len(jobs) != 1 || jobs[0].Name != "gcp" {
+		t.Errorf("unexpected failed jobs %+v: %v", jobs, err)
+	}
+}
@@ -0,0 +1,123 @@
+package releasename
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the kind of a release, ordered from the earliest to the final build of a version
+type Kind int
+
+const (
+	// Build is a nightly or CI build, e.g. 4.19.0-0.nightly-2025-05-02-041306
+	Build Kind = iota
+	// EngineeringCandidate is an ec build, e.g. 4.20.0-ec.3
+	EngineeringCandidate
+	// FeatureCandidate is an fc build, e.g. 4.10.0-fc.1
+	FeatureCandidate
+	// ReleaseCandidate is an rc build, e.g. 4.19.0-rc.2
+	ReleaseCandidate
+	// GA is a released version, e.g. 4.18.12 or 4.19.0-okd-scos.1
+	GA
+)
+
+// timestampLayout is the layout of the timestamp ending the name of the builds
+const timestampLayout = "2006-01-02-150405"
+
+var (
+	versionPattern   = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?$`)
+	buildPattern     = regexp.MustCompile(`^(?:0\.)?([a-z][a-z0-9-]*?)-(\d{4}-\d{2}-\d{2}-\d{6})$`)
+	candidatePattern = regexp.MustCompile(`^(?:([a-z][a-z0-9-]*)\.)?(ec|fc|rc)\.(\d+)$`)
+	streamPattern    = regexp.MustCompile(`^([a-z][a-z0-9-]*?)(?:\.(\d+))?$`)
+)
+
+// Name is a parsed OCP or OKD release name
+type Name struct {
+	Major, Minor, Patch int
+	Kind                Kind
+	// Stream is the stream qualifying the name, e.g. nightly, ci, nightly-multi or okd-scos
+	Stream string
+	// Timestamp is the creation time of a build
+	Timestamp time.Time
+	// Number is the number of a candidate, or of an OKD release such as 4.19.0-okd-scos.1
+	Number int
+	// Raw is the name as parsed
+	Raw string
+}
+
+// Parse parses a release name such as 4.19.0, 4.20.0-ec.3, 4.19.0-0.nightly-2025-05-02-041306,
+// 4.20.0-0.okd-scos-2025-05-02-123456 or 4.20.0-okd-scos.ec.1
+func Parse(name string) (Name, error) {
+	n := Name{Raw: name}
+	version, rest, _ := strings.Cut(name, "-")
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return n, fmt.Errorf("invalid release name %s: %s is not a version", name, version)
+	}
+	n.Major, _ = strconv.Atoi(match[1])
+	n.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		n.Patch, _ = strconv.Atoi(match[3])
+	}
+	if rest == "" {
+		n.Kind = GA
+		return n, nil
+	}
+	if match := buildPattern.FindStringSubmatch(rest); match != nil {
+		timestamp, err := time.Parse(timestampLayout, match[2])
+		if err != nil {
+			return n, fmt.Errorf("invalid release name %s: %w", name, err)
+		}
+		n.Kind, n.Stream, n.Timestamp = Build, match[1], timestamp
+		return n, nil
+	}
+	if match := candidatePattern.FindStringSubmatch(rest); match != nil {
+		n.Kind = map[string]Kind{"ec": EngineeringCandidate, "fc": FeatureCandidate, "rc": ReleaseCandidate}[match[2]]
+		n.Stream = match[1]
+		n.Number, _ = strconv.Atoi(match[3])
+		return n, nil
+	}
+	if match := streamPattern.FindStringSubmatch(rest); match != nil {
+		n.Kind, n.Stream = GA, match[1]
+		if match[2] != "" {
+			n.Number, _ = strconv.Atoi(match[2])
+		}
+		return n, nil
+	}
+	return n, fmt.Errorf("invalid release name %s: unknown suffix %s", name, rest)
+}
+
+// Compare orders two release names by version, then kind, then build time or number.
+// Builds of different streams are ordered by their timestamp.
+func (n Name) Compare(o Name) int {
+	return cmp.Or(
+		cmp.Compare(n.Major, o.Major),
+		cmp.Compare(n.Minor, o.Minor),
+		cmp.Compare(n.Patch, o.Patch),
+		cmp.Compare(n.Kind, o.Kind),
+		n.Timestamp.Compare(o.Timestamp),
+		cmp.Compare(n.Number, o.Number),
+		strings.Compare(n.Stream, o.Stream),
+		strings.Compare(n.Raw, o.Raw),
+	)
+}
+
+// Compare orders two release names, names which cannot be parsed sort before the
+// others, in lexical order
+func Compare(a, b string) int {
+	na, errA := Parse(a)
+	nb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return na.Compare(nb)
+}
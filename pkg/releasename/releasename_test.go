@@ -0,0 +1,60 @@
+package releasename
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for name, want := range map[string]Name{
+		"4.18.12":                            {Major: 4, Minor: 18, Patch: 12, Kind: GA},
+		"4.20.0-ec.3":                        {Major: 4, Minor: 20, Kind: EngineeringCandidate, Number: 3},
+		"4.19.0-rc.2":                        {Major: 4, Minor: 19, Kind: ReleaseCandidate, Number: 2},
+		"4.19.0-0.nightly-2025-05-02-041306": {Major: 4, Minor: 19, Kind: Build, Stream: "nightly"},
+		"4.19.0-0.nightly-multi-2025-05-02-041306": {Major: 4, Minor: 19, Kind: Build, Stream: "nightly-multi"},
+		"4.20.0-0.okd-scos-2025-05-02-123456":      {Major: 4, Minor: 20, Kind: Build, Stream: "okd-scos"},
+		"4.20.0-okd-scos.ec.1":                     {Major: 4, Minor: 20, Kind: EngineeringCandidate, Stream: "okd-scos", Number: 1},
+		"4.19.0-okd-scos.1":                        {Major: 4, Minor: 19, Kind: GA, Stream: "okd-scos", Number: 1},
+	} {
+		got, err := Parse(name)
+		if err != nil {
+			t.Errorf("error parsing %s: %v", name, err)
+			continue
+		}
+		if got.Major != want.Major || got.Minor != want.Minor || got.Patch != want.Patch || got.Kind != want.Kind || got.Stream != want.Stream || got.Number != want.Number {
+			t.Errorf("unexpected parse of %s: %+v", name, got)
+		}
+		if want.Kind == Build && got.Timestamp.IsZero() {
+			t.Errorf("expected a timestamp in %s", name)
+		}
+	}
+	for _, name := range []string{"", "latest", "4.19.0-0.nightly-2025-13-02-041306", "4.19.0-EC.1"} {
+		if _, err := Parse(name); err == nil {
+			t.Errorf("expected %q not to parse", name)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	sorted := []string{
+		"4.9.0",
+		"4.10.0-0.nightly-2025-01-01-000000",
+		"4.10.0-0.ci-2025-01-02-000000",
+		"4.10.0-ec.2",
+		"4.10.0-fc.1",
+		"4.10.0-rc.1",
+		"4.10.0-rc.10",
+		"4.10.0",
+		"4.10.3",
+		"4.10.12",
+	}
+	names := slices.Clone(sorted)
+	slices.Reverse(names)
+	slices.SortFunc(names, Compare)
+	if !slices.Equal(names, sorted) {
+		t.Errorf("unexpected order %v", names)
+	}
+	if Compare("latest", "4.1.0") >= 0 {
+		t.Errorf("expected names which cannot be parsed to sort first")
+	}
+}
@@ -1,15 +1,17 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
 	configv1 "github.com/openshift/api/config/v1"
 )
 
 // Load ClusterVersion object from file
-func LoadClusterVersionFromFile(filePath string) (*configv1.ClusterVersion, error) {
-	data, err := FetchURL(filePath)
+func LoadClusterVersionFromFile(ctx context.Context, src artifacts.ArtifactSource, filePath string) (*configv1.ClusterVersion, error) {
+	data, err := src.Fetch(ctx, filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"strings"
 
 	"github.com/agnivade/levenshtein"
@@ -30,13 +31,17 @@ func max(a, b int) int {
 }
 
 // DeduplicateLogsWithWindow removes similar lines based on relative similarity threshold
-// within a sliding window of the last `windowSize` lines.
-func DeduplicateLogsWithWindow(input string, threshold float64, windowSize int) string {
+// within a sliding window of the last `windowSize` lines. It returns ctx.Err() if ctx is
+// done before the whole input has been processed.
+func DeduplicateLogsWithWindow(ctx context.Context, input string, threshold float64, windowSize int) (string, error) {
 	reader := bufio.NewReader(strings.NewReader(input))
 	var buffer bytes.Buffer
 	var recentLines []string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
 		line, err := reader.ReadString('\n')
 		if err != nil {
 			if err.Error() != "EOF" {
@@ -73,5 +78,5 @@ func DeduplicateLogsWithWindow(input string, threshold float64, windowSize int)
 			break
 		}
 	}
-	return buffer.String()
+	return buffer.String(), nil
 }
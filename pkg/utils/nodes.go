@@ -1,16 +1,17 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"sort"
-	"time"
 
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func LoadNodesFromFile(path string) ([]corev1.Node, error) {
-	bytes, err := FetchURL(path)
+func LoadNodesFromFile(ctx context.Context, src artifacts.ArtifactSource, path string) ([]corev1.Node, error) {
+	bytes, err := src.Fetch(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -33,115 +34,46 @@ func FindNodeByName(nodes []corev1.Node, name string) (*corev1.Node, error) {
 	return nil, fmt.Errorf("node %s not found", name)
 }
 
-// GetNodeInfoString safely extracts and returns NodeInfo from a Node object as a formatted string
-func GetNodeInfoString(node *corev1.Node) string {
-	if node == nil {
-		return "Node is nil"
-	}
-
+// GetNodeInfo returns the system information reported by a node
+func GetNodeInfo(node *corev1.Node) api.NodeInfo {
 	info := node.Status.NodeInfo
-	// If NodeInfo is empty (all fields are zero values), this indicates it wasn't populated.
-	if info.MachineID == "" && info.SystemUUID == "" && info.KernelVersion == "" && info.OSImage == "" {
-		return fmt.Sprintf("NodeInfo is not available for node %s", node.Name)
+	return api.NodeInfo{
+		Name:                    node.Name,
+		Architecture:            info.Architecture,
+		BootID:                  info.BootID,
+		ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+		KernelVersion:           info.KernelVersion,
+		KubeProxyVersion:        info.KubeProxyVersion,
+		KubeletVersion:          info.KubeletVersion,
+		MachineID:               info.MachineID,
+		OperatingSystem:         info.OperatingSystem,
+		OSImage:                 info.OSImage,
+		SystemUUID:              info.SystemUUID,
 	}
-
-	return fmt.Sprintf(
-		`Node Info for %s:
-  Architecture: %s
-  Boot ID: %s
-  Container Runtime Version: %s
-  Kernel Version: %s
-  KubeProxy Version: %s
-  Kubelet Version: %s
-  Machine ID: %s
-  Operating System: %s
-  OS Image: %s
-  System UUID: %s`,
-		node.Name,
-		safe(info.Architecture),
-		safe(info.BootID),
-		safe(info.ContainerRuntimeVersion),
-		safe(info.KernelVersion),
-		safe(info.KubeProxyVersion),
-		safe(info.KubeletVersion),
-		safe(info.MachineID),
-		safe(info.OperatingSystem),
-		safe(info.OSImage),
-		safe(info.SystemUUID),
-	)
 }
 
-// safe returns a placeholder if the string is empty
-func safe(field string) string {
-	if field == "" {
-		return "<not available>"
-	}
-	return field
+// GetNodeLabels returns all labels from node metadata
+func GetNodeLabels(node *corev1.Node) api.NodeLabels {
+	return api.NodeLabels{Name: node.Name, Labels: node.Labels}
 }
 
-// GetNodeLabelsString returns all labels from node metadata as a string
-func GetNodeLabelsString(node *corev1.Node) string {
-	if node == nil {
-		return "Node is nil"
-	}
-
-	if len(node.Labels) == 0 {
-		return fmt.Sprintf("No labels found for node %s", node.Name)
-	}
-
-	var keys []string
-	for k := range node.Labels {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	output := fmt.Sprintf("Labels for node %s:\n", node.Name)
-	for _, k := range keys {
-		output += fmt.Sprintf("  %s: %s\n", k, node.Labels[k])
-	}
-	return output
+// GetNodeAnnotations returns all annotations from node metadata
+func GetNodeAnnotations(node *corev1.Node) api.NodeAnnotations {
+	return api.NodeAnnotations{Name: node.Name, Annotations: node.Annotations}
 }
 
-// GetNodeAnnotationsString returns all annotations from node metadata as a string
-func GetNodeAnnotationsString(node *corev1.Node) string {
-	if node == nil {
-		return "Node is nil"
-	}
-
-	if len(node.Annotations) == 0 {
-		return fmt.Sprintf("No annotations found for node %s", node.Name)
-	}
-
-	var keys []string
-	for k := range node.Annotations {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	output := fmt.Sprintf("Annotations for node %s:\n", node.Name)
-	for _, k := range keys {
-		output += fmt.Sprintf("  %s: %s\n", k, node.Annotations[k])
-	}
-	return output
-}
-
-// GetNodeConditionsString returns node condition information as a formatted string
-func GetNodeConditionsString(node *corev1.Node) string {
-	if node == nil {
-		return "Node is nil"
-	}
-
-	if len(node.Status.Conditions) == 0 {
-		return fmt.Sprintf("No conditions found for node %s", node.Name)
-	}
-
-	output := fmt.Sprintf("Conditions for node %s:\n", node.Name)
+// GetNodeConditions returns the status conditions of a node
+func GetNodeConditions(node *corev1.Node) api.NodeConditions {
+	conditions := api.NodeConditions{Name: node.Name, Conditions: []api.Condition{}}
 	for _, cond := range node.Status.Conditions {
-		output += fmt.Sprintf("  - Type: %s\n", cond.Type)
-		output += fmt.Sprintf("    Status: %s\n", cond.Status)
-		output += fmt.Sprintf("    Reason: %s\n", cond.Reason)
-		output += fmt.Sprintf("    Message: %s\n", cond.Message)
-		output += fmt.Sprintf("    LastTransitionTime: %s\n", cond.LastTransitionTime.Format(time.RFC3339))
-	}
-	return output
+		transition := cond.LastTransitionTime.Time
+		conditions.Conditions = append(conditions.Conditions, api.Condition{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: &transition,
+		})
+	}
+	return conditions
 }
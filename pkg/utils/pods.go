@@ -1,15 +1,18 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func LoadPodsFromFile(path string) ([]corev1.Pod, error) {
-	bytes, err := FetchURL(path)
+func LoadPodsFromFile(ctx context.Context, src artifacts.ArtifactSource, path string) ([]corev1.Pod, error) {
+	bytes, err := src.Fetch(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -23,12 +26,22 @@ func LoadPodsFromFile(path string) ([]corev1.Pod, error) {
 	return podList.Items, nil
 }
 
-func AllPodsSummary(pods []corev1.Pod) string {
-	var b strings.Builder
+// podSummary returns the summary of a pod, without any reason
+func podSummary(pod *corev1.Pod) api.PodSummary {
+	return api.PodSummary{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Node:      pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+	}
+}
+
+func AllPodsSummary(pods []corev1.Pod) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
-		fmt.Fprintf(&b, "%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName, pod.Status.Phase)
+		summaries = append(summaries, podSummary(&pod))
 	}
-	return b.String()
+	return summaries
 }
 
 func GetPodsByNamespace(pods []corev1.Pod, namespace string) []corev1.Pod {
@@ -41,10 +54,8 @@ func GetPodsByNamespace(pods []corev1.Pod, namespace string) []corev1.Pod {
 	return filteredPods
 }
 
-// GetContainerNamesInPod returns a string of container names in the specified pod.
-func GetContainerNamesInPod(pods []corev1.Pod, podName string) string {
-	var names []string
-
+// GetContainerNamesInPod returns the names of the containers in the specified pod.
+func GetContainerNamesInPod(pods []corev1.Pod, podName string) (*api.PodContainers, error) {
 	//Get pod from the list of pods
 	var pod *corev1.Pod
 	for _, p := range pods {
@@ -54,72 +65,61 @@ func GetContainerNamesInPod(pods []corev1.Pod, podName string) string {
 		}
 	}
 	if pod == nil {
-		return fmt.Sprintf("Pod %s not found.", podName)
+		return nil, fmt.Errorf("pod %s not found", podName)
 	}
 
+	containers := &api.PodContainers{Namespace: pod.Namespace, Pod: pod.Name}
 	for _, c := range pod.Spec.InitContainers {
-		names = append(names, c.Name)
+		containers.InitContainers = append(containers.InitContainers, c.Name)
 	}
 
 	for _, c := range pod.Spec.Containers {
-		names = append(names, c.Name)
+		containers.Containers = append(containers.Containers, c.Name)
 	}
 
 	for _, c := range pod.Spec.EphemeralContainers {
-		names = append(names, c.Name)
+		containers.EphemeralContainers = append(containers.EphemeralContainers, c.Name)
 	}
-
-	if len(names) == 0 {
-		return "No containers found in pod."
-	}
-	return strings.Join(names, " ")
+	return containers, nil
 }
 
-func RunningPodsSummary(pods []corev1.Pod) string {
-	var b strings.Builder
+func RunningPodsSummary(pods []corev1.Pod) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
 		if pod.Status.Phase == corev1.PodRunning {
 			running := true
 			for _, cs := range pod.Status.ContainerStatuses {
-				if cs.State.Running != nil {
-					continue
-				} else {
+				if cs.State.Running == nil {
 					running = false
 					break
 				}
 			}
 			if running {
-				fmt.Fprintf(&b, "%s/%s on %s: Running\n", pod.Namespace, pod.Name, pod.Spec.NodeName)
+				summaries = append(summaries, podSummary(&pod))
 			}
 		}
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return "No pods in Running state."
-	}
-	return b.String()
+	return summaries
 }
 
-func CrashLoopBackOffSummary(pods []corev1.Pod) string {
-	var b strings.Builder
+func CrashLoopBackOffSummary(pods []corev1.Pod) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
 		for _, cs := range pod.Status.ContainerStatuses {
 			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
-				fmt.Fprintf(&b, "%s/%s on %s: %s (%s)\n", pod.Namespace, pod.Name, pod.Spec.NodeName, cs.State.Waiting.Reason, cs.State.Waiting.Message)
+				summary := podSummary(&pod)
+				summary.Container = cs.Name
+				summary.Reason = cs.State.Waiting.Reason
+				summary.Message = cs.State.Waiting.Message
+				summaries = append(summaries, summary)
 			}
 		}
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return "No pods in CrashLoopBackOff state."
-	}
-	return b.String()
+	return summaries
 }
 
-func PendingPodsSummary(pods []corev1.Pod) string {
-	var b strings.Builder
+func PendingPodsSummary(pods []corev1.Pod) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
 		if pod.Status.Phase == corev1.PodPending {
 			reason := pod.Status.Reason
@@ -135,91 +135,87 @@ func PendingPodsSummary(pods []corev1.Pod) string {
 			if reason == "" {
 				reason = "Unknown"
 			}
-			fmt.Fprintf(&b, "%s/%s on %s: Pending (%s)\n", pod.Namespace, pod.Name, pod.Spec.NodeName, reason)
+			summary := podSummary(&pod)
+			summary.Reason = reason
+			summaries = append(summaries, summary)
 		}
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return "No pods in Pending state."
-	}
-	return b.String()
+	return summaries
 }
 
-func InitStateSummary(pods []corev1.Pod) string {
-	var b strings.Builder
+func InitStateSummary(pods []corev1.Pod) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
 		for _, cs := range pod.Status.InitContainerStatuses {
 			if cs.State.Waiting != nil && strings.Contains(cs.State.Waiting.Reason, "Init") {
-				fmt.Fprintf(&b, "%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName, cs.State.Waiting.Reason)
+				summary := podSummary(&pod)
+				summary.Container = cs.Name
+				summary.Reason = cs.State.Waiting.Reason
+				summaries = append(summaries, summary)
 				break
 			}
 		}
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return "No pods in Init state."
-	}
-	return b.String()
+	return summaries
 }
 
-func ErrorStateSummary(pods []corev1.Pod) string {
-	var b strings.Builder
+func ErrorStateSummary(pods []corev1.Pod) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
 		for _, cs := range pod.Status.ContainerStatuses {
 			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "Error" {
-				fmt.Fprintf(&b, "%s/%s: %s\n", pod.Namespace, pod.Name, cs.State.Waiting.Reason)
+				summary := podSummary(&pod)
+				summary.Container = cs.Name
+				summary.Reason = cs.State.Waiting.Reason
+				summaries = append(summaries, summary)
 				break
 			}
 			if cs.State.Terminated != nil && cs.State.Terminated.Reason == "Error" {
-				fmt.Fprintf(&b, "%s/%s: %s\n", pod.Namespace, pod.Name, cs.State.Terminated.Reason)
+				summary := podSummary(&pod)
+				summary.Container = cs.Name
+				summary.Reason = cs.State.Terminated.Reason
+				summaries = append(summaries, summary)
 				break
 			}
 		}
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return "No pods in Error state."
-	}
-	return b.String()
+	return summaries
 }
 
-func FilterPodsByNamespaceAsString(pods []corev1.Pod, namespace string) string {
-	var b strings.Builder
+// FilterPodsByNamespace returns the pods of the namespace, along with the reason
+// of the first failing container of each pod
+func FilterPodsByNamespace(pods []corev1.Pod, namespace string) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
-		if pod.Namespace == namespace {
-			fmt.Fprintf(&b, "%s/%s on %s: ", pod.Namespace, pod.Name, pod.Spec.NodeName)
-			for _, cs := range pod.Status.ContainerStatuses {
-				if cs.State.Waiting != nil && (cs.State.Waiting.Reason == "Error" || cs.State.Waiting.Reason == "CrashLoopBackOff" || cs.State.Waiting.Reason == "Init") {
-					fmt.Fprintf(&b, "%s %s\n", cs.State.Waiting.Reason, cs.State.Waiting.Message)
-					break
-				}
-				if cs.State.Terminated != nil && cs.State.Terminated.Reason == "Error" {
-					fmt.Fprintf(&b, "%s %s\n", cs.State.Terminated.Reason, cs.State.Terminated.Message)
-					break
-				}
+		if pod.Namespace != namespace {
+			continue
+		}
+		summary := podSummary(&pod)
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil && (cs.State.Waiting.Reason == "Error" || cs.State.Waiting.Reason == "CrashLoopBackOff" || cs.State.Waiting.Reason == "Init") {
+				summary.Container = cs.Name
+				summary.Reason = cs.State.Waiting.Reason
+				summary.Message = cs.State.Waiting.Message
+				break
+			}
+			if cs.State.Terminated != nil && cs.State.Terminated.Reason == "Error" {
+				summary.Container = cs.Name
+				summary.Reason = cs.State.Terminated.Reason
+				summary.Message = cs.State.Terminated.Message
+				break
 			}
 		}
+		summaries = append(summaries, summary)
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return fmt.Sprintf("No pods found in namespace %s.", namespace)
-	}
+	return summaries
 }
 
-func FilterPodsByNodeAsString(pods []corev1.Pod, nodeName string) string {
-	var b strings.Builder
+func FilterPodsByNode(pods []corev1.Pod, nodeName string) []api.PodSummary {
+	summaries := []api.PodSummary{}
 	for _, pod := range pods {
 		if pod.Spec.NodeName == nodeName {
-			fmt.Fprintf(&b, "%s/%s on %s: %s\n", pod.Namespace, pod.Name, pod.Spec.NodeName, pod.Status.Phase)
+			summaries = append(summaries, podSummary(&pod))
 		}
 	}
-	if b.Len() > 0 {
-		return b.String()
-	} else {
-		return fmt.Sprintf("No pods found on node %s.", nodeName)
-	}
+	return summaries
 }
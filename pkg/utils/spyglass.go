@@ -1,13 +1,14 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"regexp"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
 )
 
 // Types originally from origin monitorapi package
@@ -41,7 +42,8 @@ type Report struct {
 	Items []EventInterval `json:"items"`
 }
 
-func GetSpyglassFileNames(logsPath, testName, stepFolder string) ([]string, error) {
+// GetSpyglassFileNames returns the names of the spyglass interval files written by a test step
+func GetSpyglassFileNames(ctx context.Context, src artifacts.ArtifactSource, testName, stepFolder string) ([]string, error) {
 	// Compile the regex pattern
 	pattern := `e2e-timelines_spyglass_.*\.json$`
 	re, err := regexp.Compile(pattern)
@@ -49,50 +51,32 @@ func GetSpyglassFileNames(logsPath, testName, stepFolder string) ([]string, erro
 		return nil, fmt.Errorf("invalid regex pattern: %w", err)
 	}
 
-	// Make the HTTP GET request
-	artifactURL := fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/%s/artifacts/junit/", logsPath, testName, stepFolder)
-	resp, err := http.Get(artifactURL)
+	names, err := src.List(ctx, fmt.Sprintf("artifacts/%s/%s/artifacts/junit/", testName, stepFolder))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("non-OK HTTP status: %s", resp.Status)
-	}
-
-	// Parse the HTML content
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, err
 	}
 
 	// Collect matching file names
 	var matches []string
-	doc.Find("a").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		if re.MatchString(text) {
-			matches = append(matches, text)
+	for _, name := range names {
+		if re.MatchString(name) {
+			matches = append(matches, name)
 		}
-	})
+	}
 
 	return matches, nil
 }
 
-func GetErrorAndWarningFromSpyglassFile(spyglassFilePath string) (string, error) {
-	resp, err := http.Get(spyglassFilePath)
+// GetErrorAndWarningFromSpyglassFile returns the error and warning intervals recorded in a spyglass file
+func GetErrorAndWarningFromSpyglassFile(ctx context.Context, src artifacts.ArtifactSource, spyglassFilePath string) ([]EventInterval, error) {
+	data, err := src.Fetch(ctx, spyglassFilePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch spyglass file: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("non-OK HTTP status: %s", resp.Status)
+		return nil, fmt.Errorf("failed to fetch spyglass file: %w", err)
 	}
 
 	var events Report
-	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
-		return "", fmt.Errorf("failed to decode spyglass file: %w %s", err, spyglassFilePath)
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		return nil, fmt.Errorf("failed to decode spyglass file %s: %w", spyglassFilePath, err)
 	}
 
 	var result []EventInterval
@@ -101,35 +85,18 @@ func GetErrorAndWarningFromSpyglassFile(spyglassFilePath string) (string, error)
 			result = append(result, event)
 		}
 	}
-	if len(result) == 0 {
-		return "no errors and warnings!", nil
-	}
-	//convert event to JSON string
-	resultStr, err := json.Marshal(result)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal events: %w", err)
-	}
-	return string(resultStr), nil
+	return result, nil
 }
 
-// Given a test name check for the Locator.Keys objects in the spyglass data to see if there is an entry with key "e2e-test" that matches the test name. return result as string
-func GetSpyglassDataRelevantToTestFailure(spyglassFilePath, testName string) (string, error) {
-	errorEvents, err := GetErrorAndWarningFromSpyglassFile(spyglassFilePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to get error and warning events: %w", err)
-	}
-	if errorEvents == "" {
-		return "no spyglass data", nil // No errors or warnings found
-	}
-	var errorEventObj []EventInterval
-	err = json.Unmarshal([]byte(errorEvents), &errorEventObj)
+// GetSpyglassDataRelevantToTestFailure returns the error and warning events of a spyglass file, up to
+// the first one whose "e2e-test" locator key matches the test name
+func GetSpyglassDataRelevantToTestFailure(ctx context.Context, src artifacts.ArtifactSource, spyglassFilePath, testName string) ([]api.SpyglassEvent, error) {
+	errorEvents, err := GetErrorAndWarningFromSpyglassFile(ctx, src, spyglassFilePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal error events: %w %s", err, spyglassFilePath)
+		return nil, fmt.Errorf("failed to get error and warning events: %w", err)
 	}
-	testFound := false
-	var relevantEvents string
-	for _, event := range errorEventObj {
-		//Extract only the "Source" field and the message.HumanMessage field and construct it as a string and append it to relevantEvents
+	var relevantEvents []api.SpyglassEvent
+	for _, event := range errorEvents {
 		if event.Source == "" || event.StructuredMessage.HumanMessage == "" {
 			continue // Skip events without Source or HumanMessage
 		}
@@ -139,34 +106,19 @@ func GetSpyglassDataRelevantToTestFailure(spyglassFilePath, testName string) (st
 		if event.From == nil || event.To == nil {
 			continue // Skip events without a time range
 		}
-		testLocator := event.StructuredLocator.Keys["e2e-test"]
-		if testLocator == "" {
-			testLocator = "Not a test"
-		}
-		// Construct the relevant event string
-		eventString := fmt.Sprintf("Source: %s Type: %s Locator: 'test: %s' Reason: %s HumanMessage: %s From: %s To: %s\n",
-			event.Source,
-			event.StructuredLocator.Type,
-			testLocator,
-			event.StructuredMessage.Reason,
-			event.StructuredMessage.HumanMessage,
-			event.From.Format(time.RFC3339),
-			event.To.Format(time.RFC3339),
-		)
-
-		relevantEvents += eventString
-		for _, key := range event.StructuredLocator.Keys {
-			if key == "e2e-test" && event.StructuredLocator.Keys["e2e-test"] == testName {
-				testFound = true
-				break
-			}
-		}
-		if testFound {
+		test := event.StructuredLocator.Keys["e2e-test"]
+		relevantEvents = append(relevantEvents, api.SpyglassEvent{
+			Source:  event.Source,
+			Type:    event.StructuredLocator.Type,
+			Test:    test,
+			Reason:  event.StructuredMessage.Reason,
+			Message: event.StructuredMessage.HumanMessage,
+			From:    *event.From,
+			To:      *event.To,
+		})
+		if testName != "" && test == testName {
 			break // Stop searching once we find the test
 		}
 	}
-	if relevantEvents == "" {
-		return "no relevant data", nil // No relevant events found
-	}
 	return relevantEvents, nil
 }
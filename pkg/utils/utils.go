@@ -1,45 +1,35 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
 
 	api "github.com/Prashanth684/releasecontroller-mcp-server/pkg/api"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/artifacts"
+	"github.com/Prashanth684/releasecontroller-mcp-server/pkg/httpclient"
 )
 
 // FetchURL fetches data from the given URL and returns it as a string
-func FetchURL(url string) (string, error) {
-	resp, err := http.Get(url)
+func FetchURL(ctx context.Context, url string) (string, error) {
+	data, err := httpclient.Default().Get(ctx, url)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	return string(body), nil
+	return string(data), nil
 }
 
 // FetchJSONBytes fetches JSON data from the given URL and returns it as a byte slice.
-func FetchJSONBytes(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// The URL is expected to be a release controller API endpoint, whose responses are
+// cached for a short time only since they change as new payloads are built.
+func FetchJSONBytes(ctx context.Context, url string) ([]byte, error) {
+	data, err := httpclient.Default().GetMutable(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching URL: %w", err)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("non-200 response: %d %s", resp.StatusCode, resp.Status)
-	}
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
-	}
 	return data, nil
 }
 
@@ -186,13 +176,18 @@ func ExtractStepName(logLine string) (string, error) {
 	return strings.TrimSpace(match[1]), nil
 }
 
-func CompactTestLogs(input string, threshold float64) string {
+// CompactTestLogs drops the noise from e2e test logs and deduplicates similar lines,
+// returning early with ctx.Err() when ctx is done
+func CompactTestLogs(ctx context.Context, input string, threshold float64) (string, error) {
 	lines := strings.Split(input, "\n")
 	var b strings.Builder
 
 	if threshold > 0.6 {
 		inBlock := false
 		for _, line := range lines {
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
 			if !inBlock && strings.HasPrefix(line, "started:") {
 				inBlock = true
 			}
@@ -212,16 +207,17 @@ func CompactTestLogs(input string, threshold float64) string {
 
 	// Couldn't compact any logs, return the original input
 	if len(b.String()) <= 0 {
-		return DeduplicateLogsWithWindow(input, threshold, 5)
+		return DeduplicateLogsWithWindow(ctx, input, threshold, 5)
 	}
-	return DeduplicateLogsWithWindow(b.String(), threshold, 5)
+	return DeduplicateLogsWithWindow(ctx, b.String(), threshold, 5)
 }
 
-func ExtractFailingTestsBlock(input string) (string, error) {
-	lines := strings.Split(input, "\n")
-	var b strings.Builder
+// ExtractFailingTests returns the names of the tests listed in the "Failing tests:"
+// block of an openshift-tests log, and the lines of the block as they were logged
+func ExtractFailingTests(input string) ([]string, []string, error) {
+	var tests, block []string
 	inBlock := false
-	for _, line := range lines {
+	for _, line := range strings.Split(input, "\n") {
 		if strings.Contains(line, "Failing tests:") {
 			inBlock = true
 		}
@@ -230,32 +226,47 @@ func ExtractFailingTestsBlock(input string) (string, error) {
 			if strings.Contains(line, "Writing JUnit report to") {
 				break
 			}
-			b.WriteString(line + "\n")
+			block = append(block, line)
+			if test := strings.TrimSpace(line); test != "" && !strings.Contains(line, "Failing tests:") {
+				tests = append(tests, test)
+			}
 		}
 	}
 	// If no failing tests block was found, return error
-	if len(b.String()) <= 0 {
-		return "No failing tests found.", fmt.Errorf("no failing tests block found in the input")
+	if !inBlock {
+		return nil, nil, fmt.Errorf("no failing tests block found in the input")
 	}
-	return b.String(), nil
+	return tests, block, nil
 }
 
-func ExtractFlakyTestsBlock(input string) (string, error) {
-	lines := strings.Split(input, "\n")
-	var b strings.Builder
+// ExtractFlakyTests returns the names of the tests reported as flaked, either in
+// "flaked:" lines or in the "Flaky tests:" block of an openshift-tests log, and the
+// lines they were reported in as they were logged
+func ExtractFlakyTests(input string) ([]string, []string, error) {
+	var tests, block []string
+	seen := map[string]bool{}
+	add := func(test string) {
+		if test != "" && !seen[test] {
+			seen[test] = true
+			tests = append(tests, test)
+		}
+	}
 	// Regex to match flaked line and extract content inside quotes
 	flakedRegex := regexp.MustCompile(`flaked:.*?"([^"]+)"`)
 	inBlock := false
 	skippedEmptyAfterFlaky := false
-	for _, line := range lines {
+	for _, line := range strings.Split(input, "\n") {
 		// Handle flaked lines with quoted test names
 		if matches := flakedRegex.FindStringSubmatch(line); len(matches) == 2 {
-			b.WriteString(line + "\n")
+			block = append(block, line)
+			add(matches[1])
 			continue
 		}
 
 		if strings.Contains(line, "Flaky tests:") {
 			inBlock = true
+			block = append(block, line)
+			continue
 		}
 		if inBlock {
 			// Skip exactly one empty line after "Flaky tests:"
@@ -267,14 +278,15 @@ func ExtractFlakyTestsBlock(input string) (string, error) {
 			if strings.TrimSpace(line) == "" {
 				break
 			}
-			b.WriteString(line + "\n")
+			block = append(block, line)
+			add(strings.TrimSpace(line))
 		}
 	}
-	// If no flaky tests block was found, return error
-	if len(b.String()) <= 0 {
-		return "No flaky tests found.", fmt.Errorf("no flaky tests block found in the input")
+	// If no flaky tests were found, return error
+	if len(block) == 0 {
+		return nil, nil, fmt.Errorf("no flaky tests block found in the input")
 	}
-	return b.String(), nil
+	return tests, block, nil
 }
 
 func ExtractMonitorTestFailures(input string) (string, error) {
@@ -332,7 +344,7 @@ func ExtractFailedJobsFromAggregate(logData string) map[string]bool {
 	return result
 }
 
-func FetchAggregateJobFailures(baseUrl, logData string) (string, error) {
+func FetchAggregateJobFailures(ctx context.Context, src artifacts.ArtifactSource, dir, logData string) (string, error) {
 	failJobMap := ExtractFailedJobsFromAggregate(logData)
 	if len(failJobMap) == 0 {
 		return "", fmt.Errorf("no failed jobs found in the provided log data")
@@ -343,9 +355,9 @@ func FetchAggregateJobFailures(baseUrl, logData string) (string, error) {
 			continue
 		}
 
-		data, err := FetchURL(baseUrl + "/" + job + "/" + job + ".log")
+		data, err := src.Fetch(ctx, dir+"/"+job+"/"+job+".log")
 		if err != nil {
-			return "", fmt.Errorf("error fetching URL %q: %w", baseUrl, err)
+			return "", fmt.Errorf("error fetching %q: %w", dir, err)
 		}
 		// Find the index of the "summary:" line
 		idx := strings.Index(data, "summary:")
@@ -358,36 +370,16 @@ func FetchAggregateJobFailures(baseUrl, logData string) (string, error) {
 	return builder.String(), nil
 }
 
-func GetGatherExtraFolderPath(prowurl string) (string, error) {
-	logsPath := ExtractPath(prowurl)
-	if logsPath == "" {
-		return "", fmt.Errorf("invalid Prow job URL: %s", prowurl)
-	}
-	testName, err := ExtractTestNameFromURL(prowurl)
+// GetGatherExtraFolderPath returns the path of the gather-extra artifacts relative to the job root
+func GetGatherExtraFolderPath(src artifacts.ArtifactSource) (string, error) {
+	testName, err := ExtractTestNameFromURL(src.JobName())
 	if err != nil {
 		return "", fmt.Errorf("error fetching test name: %w", err)
 	}
-	gatherExtraURL := fmt.Sprintf("https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/%s/artifacts/%s/gather-extra/artifacts/", logsPath, testName)
-	return gatherExtraURL, nil
+	return fmt.Sprintf("artifacts/%s/gather-extra/artifacts/", testName), nil
 }
 
 func GetContainerLogFilePath(gatherExtraPath, podName, namespace, containerName string) string {
 	// Construct the path to the container log file
 	return fmt.Sprintf("%s/pods/%s_%s_%s.log", gatherExtraPath, namespace, podName, containerName)
 }
-
-func IndentMultiline(s, indent string) string {
-	lines := strings.Split(s, "\n")
-	for i, line := range lines {
-		lines[i] = indent + line
-	}
-	return strings.Join(lines, "\n")
-}
-
-func ExtractPath(url string) string {
-	const prefix = "https://prow.ci.openshift.org/view/gs/"
-	if strings.HasPrefix(url, prefix) {
-		return strings.TrimPrefix(url, prefix)
-	}
-	return ""
-}